@@ -10,8 +10,10 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"maps"
 	"math/big"
 	"os"
+	"path/filepath"
 	"slices"
 	"strconv"
 	"strings"
@@ -27,22 +29,30 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/config"
 	"github.com/nspcc-dev/neo-go/pkg/core"
 	"github.com/nspcc-dev/neo-go/pkg/core/interop"
+	"github.com/nspcc-dev/neo-go/pkg/core/interop/interopnames"
 	"github.com/nspcc-dev/neo-go/pkg/core/interop/runtime"
 	"github.com/nspcc-dev/neo-go/pkg/core/native"
 	"github.com/nspcc-dev/neo-go/pkg/core/state"
 	"github.com/nspcc-dev/neo-go/pkg/core/storage"
 	"github.com/nspcc-dev/neo-go/pkg/core/storage/dbconfig"
 	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/hash"
 	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
 	"github.com/nspcc-dev/neo-go/pkg/encoding/address"
+	"github.com/nspcc-dev/neo-go/pkg/encoding/base58"
 	"github.com/nspcc-dev/neo-go/pkg/encoding/bigint"
+	"github.com/nspcc-dev/neo-go/pkg/encoding/fixedn"
+	gio "github.com/nspcc-dev/neo-go/pkg/io"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract/callflag"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract/manifest"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract/nef"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract/trigger"
 	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/util/bitfield"
 	"github.com/nspcc-dev/neo-go/pkg/vm"
+	"github.com/nspcc-dev/neo-go/pkg/vm/emit"
+	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
 	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
 	"github.com/urfave/cli/v2"
 	"go.uber.org/zap"
@@ -57,6 +67,23 @@ const (
 	exitFuncKey         = "exitFunc"
 	readlineInstanceKey = "readlineKey"
 	printLogoKey        = "printLogoKey"
+	verboseKey          = "verbose"
+	readOnlyKey         = "readOnly"
+	outputKey           = "output"
+	teeKey              = "tee"
+	recordKey           = "record"
+	sessionKey          = "session"
+	goCompileCacheKey   = "goCompileCache"
+	aliasesKey          = "aliases"
+	scriptSlotsKey      = "scriptSlots"
+	activeSlotKey       = "activeSlot"
+	defaultGasKey       = "defaultGas"
+)
+
+// Limits for stack items JSON dumps.
+const (
+	dumpMaxDepth = 64
+	dumpMaxSize  = stackitem.MaxSize
 )
 
 // Various flag names.
@@ -67,8 +94,20 @@ const (
 	backwardsFlagFullName = "backwards"
 	diffFlagFullName      = "diff"
 	hashFlagFullName      = "hash"
+	appendFlagFullName    = "append"
+	dotFlagFullName       = "dot"
+	restartFlagFullName   = "restart"
+	treeFlagFullName      = "tree"
+	keepGoingFlagFullName = "keep-going"
+	forceFlagFullName     = "force"
+	assumeFlagFullName    = "assume-signed"
+	intoSyscallFlagName   = "into-syscall"
 )
 
+// maxSyscallArgs is the maximum number of parameters system interops have,
+// that's the number of topmost estack items shown before SYSCALL.
+const maxSyscallArgs = 4
+
 var (
 	historicFlag = &cli.IntFlag{
 		Name: historicFlagFullName,
@@ -83,6 +122,14 @@ var (
 		Name:  hashFlagFullName,
 		Usage: "Smart-contract hash in LE form or address",
 	}
+	appendFlag = &cli.BoolFlag{
+		Name:  appendFlagFullName,
+		Usage: "Append the script to the currently loaded one instead of replacing it",
+	}
+	forceFlag = &cli.BoolFlag{
+		Name:  forceFlagFullName,
+		Usage: "Load the script even if it exceeds the transaction script size limit",
+	}
 )
 
 var commands = []*cli.Command{
@@ -93,6 +140,49 @@ var commands = []*cli.Command{
 		Description: "Exit the VM prompt.",
 		Action:      handleExit,
 	},
+	{
+		// Handled by CLI.Eval, the entry is needed for help and autocompletion.
+		Name:      "repeat",
+		Usage:     "Execute the given command several times",
+		UsageText: `repeat <n> <command>`,
+		Description: `<n> is mandatory positive number of times to execute <command> (with all of
+its parameters and flags). Execution stops at the first command returning an
+error.
+
+Example:
+> repeat 50 step`,
+	},
+	{
+		// Handled by CLI.Eval, the entry is needed for help and autocompletion.
+		Name:      "source",
+		Usage:     "Execute commands from the file",
+		UsageText: `source [--keep-going] <file>`,
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  keepGoingFlagFullName,
+				Usage: "Report failed commands and continue execution",
+			},
+		},
+		Description: `<file> is mandatory parameter, it contains one command per line, empty lines
+and lines starting with '#' are ignored. Execution stops at the first unknown
+command or command returning an error unless --keep-going flag is given.
+
+Example:
+> source --keep-going /path/to/script.txt`,
+	},
+	{
+		Name:      "alias",
+		Usage:     "Define command alias or list defined ones",
+		UsageText: `alias [<name> <command>]`,
+		Description: `Define <name> as a shortcut for the <command> (with all of its parameters and
+flags) for the rest of the session. Aliases can't shadow command names. Without
+parameters all user-defined aliases are printed. Built-in aliases are 'c' for
+'cont', 's' for 'step', 'b' for 'break' and 'es' for 'estack'.
+
+Example:
+> alias ops10 ops 0 10`,
+		Action: handleAlias,
+	},
 	{
 		Name:        "ip",
 		Usage:       "Show current instruction",
@@ -102,12 +192,15 @@ var commands = []*cli.Command{
 	},
 	{
 		Name:      "break",
-		Usage:     "Place a breakpoint",
-		UsageText: `break <ip>`,
-		Description: `<ip> is mandatory parameter.
+		Aliases:   []string{"b"},
+		Usage:     "Place a breakpoint or remove all of them",
+		UsageText: `break <ip> | break clear`,
+		Description: `<ip> is mandatory parameter, 'clear' removes all breakpoints of the
+current context.
 
 Example:
-> break 12`,
+> break 12
+> break clear`,
 		Action: handleBreak,
 	},
 	{
@@ -141,18 +234,45 @@ Example:
 		Action: handleJump,
 	},
 	{
-		Name:        "estack",
-		Usage:       "Show evaluation stack contents",
-		UsageText:   "estack",
-		Description: "Show evaluation stack contents.",
-		Action:      handleXStack,
+		Name:      "estack",
+		Aliases:   []string{"es"},
+		Usage:     "Show evaluation stack contents",
+		UsageText: "estack [--tree]",
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  treeFlagFullName,
+				Usage: "Show stack items as an indented tree instead of JSON",
+			},
+		},
+		Description: `Show evaluation stack contents. With --tree flag every stack item is
+printed as an indented tree with the item's index on the stack (starting from
+the bottom one).
+
+Example:
+> estack --tree`,
+		Action: handleXStack,
+	},
+	{
+		Name:      "itemstats",
+		Usage:     "Show structure statistics of the top evaluation stack item",
+		UsageText: "itemstats",
+		Description: `Walk the top evaluation stack item recursively and print the number of
+items of every type found, the total number of items and the maximum nesting
+depth. Circular references are reported, but not followed. This allows to
+check the item against the serialization limits.
+
+Example:
+> itemstats`,
+		Action: handleItemStats,
 	},
 	{
-		Name:        "istack",
-		Usage:       "Show invocation stack contents",
-		UsageText:   "istack",
-		Description: "Show invocation stack contents.",
-		Action:      handleXStack,
+		Name:      "istack",
+		Usage:     "Show invocation stack contents",
+		UsageText: "istack",
+		Description: `Show invocation stack frames starting from the current (top) one. Every
+frame is printed with its index, next instruction pointer (with the opcode)
+and script hash.`,
+		Action: handleXStack,
 	},
 	{
 		Name:        "sslot",
@@ -190,12 +310,33 @@ Example:
 > loadnef /path/to/script.nef /path/to/manifest.json`,
 		Action: handleLoadNEF,
 	},
+	{
+		Name:      "validate-nef",
+		Usage:     "Check NEF file and manifest for correctness without loading them into the VM",
+		UsageText: `validate-nef <file> [<manifest>]`,
+		Description: `<file> parameter is mandatory, <manifest> parameter (if omitted) will
+   be guessed from the <file> parameter by replacing '.nef' suffix with '.manifest.json'
+   suffix.
+
+The command checks NEF file format and checksum, script correctness, manifest
+ABI consistency (method and event names and parameters) and whether manifest
+methods point to script instructions. All found issues are printed, the current
+VM state is not changed.
+
+Example:
+> validate-nef /path/to/script.nef /path/to/manifest.json`,
+		Action: handleValidateNEF,
+	},
 	{
 		Name:      "loadbase64",
 		Usage:     "Load a base64-encoded script string into the VM optionally attaching to it provided signers with scopes",
-		UsageText: `loadbase64 [--historic <height>] [--gas <int>] <string> [-- <signer-with-scope>, ...]`,
-		Flags:     []cli.Flag{historicFlag, gasFlag},
-		Description: `<string> is mandatory parameter.
+		UsageText: `loadbase64 [--historic <height>] [--gas <int>] [--append] [--force] <string> [-- <signer-with-scope>, ...]`,
+		Flags:     []cli.Flag{historicFlag, gasFlag, appendFlag, forceFlag},
+		Description: `<string> is mandatory parameter. If --append flag is set, the script is
+appended to the currently loaded one (signers of the current script are reused
+if no new ones are provided) and the resulting script is loaded from the very
+beginning. It works as a regular load if nothing is loaded. Scripts larger
+than the transaction script size limit are rejected unless --force flag is set.
 
 ` + cmdargs.SignersParsingDoc + `
 
@@ -206,22 +347,64 @@ Example:
 	{
 		Name:      "loadhex",
 		Usage:     "Load a hex-encoded script string into the VM optionally attaching to it provided signers with scopes",
-		UsageText: `loadhex [--historic <height>] [--gas <int>] <string> [-- <signer-with-scope>, ...]`,
-		Flags:     []cli.Flag{historicFlag, gasFlag},
-		Description: `<string> is mandatory parameter.
+		UsageText: `loadhex [--historic <height>] [--gas <int>] [--append] [--force] <string> [-- <signer-with-scope>, ...]`,
+		Flags:     []cli.Flag{historicFlag, gasFlag, appendFlag, forceFlag},
+		Description: `<string> is mandatory parameter. If --append flag is set, the script is
+appended to the currently loaded one (signers of the current script are reused
+if no new ones are provided) and the resulting script is loaded from the very
+beginning. It works as a regular load if nothing is loaded. Scripts larger
+than the transaction script size limit are rejected unless --force flag is set.
 
 ` + cmdargs.SignersParsingDoc + `
 
 Example:
-> loadhex 0c0c48656c6c6f20776f726c6421`,
+> loadhex 0c0c48656c6c6f20776f726c6421
+> loadhex --append 40`,
 		Action: handleLoadHex,
 	},
+	{
+		Name:      "emit",
+		Usage:     "Build a script from opcodes and push/syscall items and load it into the VM",
+		UsageText: `emit [--historic <height>] [--gas <int>] [--append] [--force] <item> ...`,
+		Flags:     []cli.Flag{historicFlag, gasFlag, appendFlag, forceFlag},
+		Description: `Build a script from the list of <item>s and load it the same way 'loadhex'
+does. Every <item> is one of:
+ * opcode name (like 'PUSH1' or 'add'), emitted as is
+ * 0x<hex> bytes, emitted as is (operand of the preceding opcode)
+ * 'int <n>', emits an instruction pushing integer <n>
+ * 'string <s>', emits an instruction pushing string <s>
+ * 'bytes <hex>', emits an instruction pushing hex-encoded byte array
+ * 'syscall <name>', emits SYSCALL with the given interop name
+--append and --force flags work the same way as for 'loadhex'.
+
+Example:
+> emit PUSH1 PUSH2 ADD
+> emit string "Hello world!" syscall System.Runtime.Log
+> emit INITSLOT 0x0100 PUSH1 STLOC0`,
+		Action: handleEmit,
+	},
+	{
+		Name:      "pushint",
+		Usage:     "Append integer push instruction to the loaded script",
+		UsageText: `pushint <value>`,
+		Description: `<value> is mandatory decimal integer parameter. The shortest push instruction
+for it (the same the compiler uses) is appended to the currently loaded script
+(or forms a new script if nothing is loaded) and the resulting script is loaded
+from the very beginning.
+
+Example:
+> pushint 5
+> pushint 100500`,
+		Action: handlePushInt,
+	},
 	{
 		Name:      "loadgo",
 		Usage:     "Compile and load a Go file with the manifest into the VM optionally attaching to it provided signers with scopes and setting provided hash",
 		UsageText: `loadgo [--historic <height>] [--gas <int>] [--hash <hash-or-address>] <file> [-- <signer-with-scope>, ...]`,
 		Flags:     []cli.Flag{historicFlag, gasFlag, hashFlag},
-		Description: `<file> is mandatory parameter.
+		Description: `<file> is mandatory parameter. Compilation results are cached for
+the session, so the file is recompiled only if its contents change (changes
+in other packages it imports are not tracked, use a new session for them).
 
 ` + cmdargs.SignersParsingDoc + `
 
@@ -270,6 +453,53 @@ Example:
 		Description: "Unload compiled script from the VM and reset context to proper (possibly, historic) state.",
 		Action:      handleReset,
 	},
+	{
+		Name:      "clear",
+		Usage:     "Clear the evaluation stack or slots of the current context",
+		UsageText: `clear estack|slots`,
+		Description: `Remove all items from the evaluation stack ('estack') or set all static,
+local and argument slot variables of the current context to Null ('slots').
+The loaded script and instruction pointer are kept intact, so the execution
+can be continued from the same point.
+
+Example:
+> clear estack`,
+		Action: handleClear,
+	},
+	{
+		Name:      "loadslot",
+		Usage:     "Load a script into the named slot and make it active",
+		UsageText: `loadslot [--historic <height>] [--gas <int>] <name> <file>`,
+		Flags:     []cli.Flag{historicFlag, gasFlag},
+		Description: `Load the script from <file> into the slot with the given <name> and make
+this slot active, so that all other commands work with it. The script of
+the previously active slot along with its VM state is kept in its slot and
+can be activated again with 'switch'. The slot that is active on start is
+named '` + defaultSlotName + `'. <file> can be a NEF file or contain hex- or base64-encoded
+script.
+
+Example:
+> loadslot old /path/to/old.nef`,
+		Action: handleLoadSlot,
+	},
+	{
+		Name:      "switch",
+		Usage:     "Make the named script slot active",
+		UsageText: `switch <name>`,
+		Description: `Make the slot with the given <name> active keeping the state of the
+currently active one.
+
+Example:
+> switch ` + defaultSlotName,
+		Action: handleSwitch,
+	},
+	{
+		Name:        "slots",
+		Usage:       "List named script slots",
+		UsageText:   "slots",
+		Description: "List named script slots, the active one is marked with '*'.",
+		Action:      handleListScriptSlots,
+	},
 	{
 		Name:      "parse",
 		Usage:     "Parse provided argument and convert it into other possible formats",
@@ -278,36 +508,161 @@ Example:
 and converted to other formats. Strings are escaped and output in quotes.`,
 		Action: handleParse,
 	},
+	{
+		Name:      "decode",
+		Usage:     "Decode the topmost evaluation stack item as a known structure",
+		UsageText: `decode <type>`,
+		Description: `Convert the topmost evaluation stack item (it's not popped) into the
+structure of the given <type> and print it in JSON. Supported types are:
+` + strings.Join(slices.Sorted(maps.Keys(decodableTypes)), ", ") + `.
+
+Example:
+> decode parameter`,
+		Action: handleDecode,
+	},
+	{
+		Name:      "reverse",
+		Usage:     "Reverse the byte order of the provided hex string",
+		UsageText: `reverse <hex>`,
+		Description: `<hex> is a hex-encoded byte array (with or without 0x prefix), it's
+printed back with the byte order reversed which is handy for LE/BE script hash
+conversions.
+
+Example:
+> reverse 0x0102ff`,
+		Action: handleReverse,
+	},
+	{
+		Name:      "eval",
+		Usage:     "Evaluate an integer expression over the current VM state",
+		UsageText: `eval <expr>`,
+		Description: `<expr> is an integer expression consisting of operands joined with
+'+', '-', '*', '/' and '%' operators (the usual precedence applies,
+parentheses are not supported). Operands can be decimal integers, 'estack.len'
+(the number of evaluation stack items) or references to items of the current
+context: 'estack[n]' (where 0 is the topmost item), 'sslot[n]', 'lslot[n]' or
+'aslot[n]'. Referenced items must be convertible to integers.
+
+Example:
+> eval estack[0] + lslot[1] * 2`,
+		Action: handleEval,
+	},
 	{
 		Name:      "run",
 		Usage:     "Usage Execute the current loaded script",
-		UsageText: `run [<method> [<parameter>...]]`,
+		UsageText: `run [--restart] [<method> [<parameter>...]]`,
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  restartFlagFullName,
+				Usage: "Start method invocation from scratch even if execution is paused",
+			},
+		},
 		Description: `<method> is a contract method, specified in manifest. It can be '_' which will push
-        parameters onto the stack and execute from the current offset.
+        parameters onto the stack and execute from the current offset. Method
+        invocation starts execution from scratch, so it's refused if execution
+        of the loaded program is paused unless --restart flag is given, use
+        'cont' to continue paused execution.
 <parameter> is a parameter (can be repeated multiple times) that can be specified
         using the same rules as for 'contract testinvokefunction' command:
 
 ` + cmdargs.ParamsParsingDoc + `
 
+        Additionally, '@<file>' parameter is a byte array with the contents of
+        the given file, '@<file>:hex' and '@<file>:base64' decode hex and base64
+        file contents correspondingly. Use 'string:@...' to pass a string
+        starting with '@'.
+
 Example:
-> run put int:5 string:some_string_value`,
+> run put int:5 string:some_string_value
+> run put key @value.bin`,
 		Action: handleRun,
 	},
 	{
-		Name:        "cont",
-		Usage:       "Continue execution of the current loaded script",
-		UsageText:   "cont",
-		Description: "Continue execution of the current loaded script.",
-		Action:      handleCont,
+		Name:      "cont",
+		Aliases:   []string{"c"},
+		Usage:     "Continue execution of the current loaded script",
+		UsageText: "cont [<n>]",
+		Description: `<n> is optional parameter to stop at the n-th breakpoint hit, previous
+hits are skipped silently.
+
+Example:
+> cont 3`,
+		Action: handleCont,
+	},
+	{
+		Name:      "until",
+		Usage:     "Run the current loaded script until the specified instruction",
+		UsageText: `until <ip>`,
+		// Negative <ip> must not be treated as a flag.
+		SkipFlagParsing: true,
+		Description: `Run the current loaded script until the instruction pointer of the current
+context reaches <ip> or execution stops (at breakpoint, FAULT or HALT). Unlike
+'break', no breakpoint is left after that.
+
+Example:
+> until 12`,
+		Action: handleUntil,
+	},
+	{
+		Name:      "dryrun",
+		Usage:     "Report witnesses required by the current loaded script",
+		UsageText: "dryrun [--assume-signed]",
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  assumeFlagFullName,
+				Usage: "Make all witness checks pass",
+			},
+		},
+		Description: `Execute the current loaded script from the beginning in a separate
+throwaway VM with the current signers and list script hashes that were
+checked along with the check results, so that a set of transaction signers
+can be assembled. Neither the current VM nor the chain state are affected.
+
+Script execution may stop at the first failed witness check, in this case
+either add the missing signer and reload the script or use --assume-signed
+flag to make all witness checks pass. Note that in the latter case the
+script may take branches that are not possible with real signers. Only
+System.Runtime.CheckWitness calls are tracked, witness checks made by native
+contracts internally (like NEP-17 transfer ones) are not reported.
+
+Example:
+> dryrun --assume-signed`,
+		Action: handleDryRun,
+	},
+	{
+		Name:      "stepuntil-fault",
+		Usage:     "Step through the current loaded script until it stops",
+		UsageText: "stepuntil-fault",
+		Description: `Execute the current loaded script instruction by instruction ignoring
+breakpoints until it either HALTs or FAULTs and print the number of steps
+taken. On FAULT the VM is left at the faulting instruction, so its state can
+be inspected.
+
+Example:
+> stepuntil-fault`,
+		Action: handleStepUntilFault,
 	},
 	{
 		Name:      "step",
+		Aliases:   []string{"s"},
 		Usage:     "Step (n) instruction in the program",
-		UsageText: `step [<n>]`,
+		UsageText: `step [--into-syscall] [<n>]`,
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  intoSyscallFlagName,
+				Usage: "Step (n) instructions one by one, pausing before any SYSCALL",
+			},
+		},
 		Description: `<n> is optional parameter to specify number of instructions to run.
 
+With --into-syscall flag instructions are executed one by one and execution is
+paused right before every SYSCALL instruction, the interop name and topmost
+evaluation stack items (interop arguments) are printed then. Repeat the
+command to execute the SYSCALL.
+
 Example:
-> step 10`,
+> step 10
+> step --into-syscall 10`,
 		Action: handleStep,
 	},
 	{
@@ -341,18 +696,137 @@ Example:
 		Action: handleStepOver,
 	},
 	{
-		Name:        "ops",
-		Usage:       "Dump opcodes of the current loaded program",
-		UsageText:   "ops",
-		Description: "Dump opcodes of the current loaded program",
-		Action:      handleOps,
+		Name:      "ops",
+		Usage:     "Dump opcodes of the current loaded program",
+		UsageText: "ops [<start> [<count>]]",
+		Description: `Dump opcodes of the current loaded program. <start> is an optional index
+of the first instruction to dump and <count> is an optional number of
+instructions to dump (all instructions till the end of the program by default).
+
+Example:
+> ops 10 5`,
+		Action: handleOps,
+	},
+	{
+		Name:      "find",
+		Usage:     "Find instructions with the given opcode or syscall in the current loaded program",
+		UsageText: `find <opcode> | find syscall <name>`,
+		Description: `Print indices of all instructions with the given opcode or of all SYSCALL
+instructions invoking the given interop in the current loaded program. The
+resulting indices can be used to place breakpoints.
+
+Example:
+> find ADD
+> find syscall System.Runtime.Notify`,
+		Action: handleFind,
+	},
+	{
+		Name:      "find-xref",
+		Usage:     "Find jump and call instructions targeting the given instruction",
+		UsageText: `find-xref <ip>`,
+		Description: `Print indices of all instructions of the current loaded program that
+statically reference the instruction at <ip>: jumps (both short and long
+forms), CALL/CALL_L, PUSHA, TRY/TRY_L (catch and finally offsets) and
+ENDTRY/ENDTRY_L. Targets of CALLA and other dynamic transfers are not known
+before execution, so they're not taken into account.
+
+Example:
+> find-xref 42`,
+		Action: handleFindXref,
+	},
+	{
+		Name:      "diff",
+		Usage:     "Compare instructions of two scripts",
+		UsageText: `diff <file1> <file2>`,
+		Description: `Disassemble scripts from both files and print instruction-level
+differences: removed ('-'), added ('+') and changed ('~') instructions with
+their indices. Files can be NEF files or contain hex- or base64-encoded
+scripts. Nothing is required to be loaded.
+
+Example:
+> diff /path/to/old.nef /path/to/new.nef`,
+		Action: handleDiff,
+	},
+	{
+		Name:      "interop",
+		Usage:     "Show interop function details",
+		UsageText: `interop price <name>`,
+		Description: `'price' prints the GAS price of the interop function with the given name
+        taking into account the current execution fee factor of the chain
+        used for VM CLI invocations. Interops that are not active at the
+        current height are treated as unknown.
+
+Example:
+> interop price System.Runtime.Log`,
+		Action: handleInterop,
+	},
+	{
+		Name:      "callgraph",
+		Usage:     "Show call sites of the current loaded program and their destinations",
+		UsageText: "callgraph [--dot]",
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  dotFlagFullName,
+				Usage: "Print call graph in DOT format",
+			},
+		},
+		Description: `Show CALL and CALLL instructions of the current loaded program along with
+their destination offsets. CALLA destinations are computed at runtime, so
+they're shown as dynamic. If --dot flag is set, the graph is printed in DOT
+format.
+
+Example:
+> callgraph --dot`,
+		Action: handleCallGraph,
+	},
+	{
+		Name:      "manifest",
+		Usage:     "Show ABI methods of the loaded contract manifest",
+		UsageText: "manifest",
+		Description: `Show ABI methods of the contract manifest loaded with 'loadgo', 'loadnef' or
+'loaddeployed' command including parameter names and types and return type.
+
+Example:
+> manifest`,
+		Action: handleManifest,
+	},
+	{
+		Name:      "hash",
+		Usage:     "Calculate hash of the loaded script and contract hash it will have after deployment",
+		UsageText: `hash [<sender>]`,
+		Description: `Calculate hash of the loaded script. If the contract manifest is loaded, then
+also calculate the contract hash it will have after deployment by the sender.
+<sender> is optional address or LE hash of the deploying account, the sender
+of the loaded transaction (if any) is used by default.
+
+Example:
+> hash NbrUYaZgyhSkNoRo9ugRyEMdUZxrhkNaWB`,
+		Action: handleHash,
+	},
+	{
+		Name:      "memusage",
+		Usage:     "Show the number of stack item references and evaluation stack items",
+		UsageText: "memusage",
+		Description: `Show the number of stack item references currently held by the VM (including
+items nested into arrays, structs and maps) compared to the maximum allowed
+number along with the number of items on the evaluation stack. Zero values
+are reported if no program is loaded.
+
+Example:
+> memusage`,
+		Action: handleMemUsage,
 	},
 	{
-		Name:        "events",
-		Usage:       "Dump events emitted by the current loaded program",
-		UsageText:   "events",
-		Description: "Dump events emitted by the current loaded program",
-		Action:      handleEvents,
+		Name:      "events",
+		Aliases:   []string{"notifications"},
+		Usage:     "Dump events emitted by the current loaded program",
+		UsageText: "events [<hash>]",
+		Description: `Dump events emitted by the current loaded program. If contract <hash> (LE
+hash or address) is given, only events emitted by this contract are dumped.
+
+Example:
+> events 0xd2a4cff31913016155e38e474a2c06d08be276cf`,
+		Action: handleEvents,
 	},
 	{
 		Name:      "env",
@@ -371,6 +845,96 @@ Example:
 > env -v`,
 		Action: handleEnv,
 	},
+	{
+		Name:      "config",
+		Usage:     "Show protocol and ledger settings of the chain used for VM CLI invocations",
+		UsageText: "config",
+		Description: `Show protocol and ledger settings affecting VM CLI invocations such as network
+magic, enabled extensions and hardfork heights. Use 'env -v' to dump the whole
+node configuration.
+
+Example:
+> config`,
+		Action: handleConfig,
+	},
+	{
+		Name:      "contracts",
+		Usage:     "List contracts deployed on the chain used for VM CLI invocations",
+		UsageText: "contracts",
+		Description: `List IDs, hashes (LE) and names of all non-native contracts deployed on the
+chain used for VM CLI invocations (historic state is taken into account if
+the current program was loaded with --historic flag). It scans the whole
+contract namespace, so it may take some time for chains with many contracts.
+
+Example:
+> contracts`,
+		Action: handleContracts,
+	},
+	{
+		Name:      "verbose",
+		Usage:     "Print Runtime.Log and Runtime.Notify messages during execution",
+		UsageText: `verbose [on|off]`,
+		Description: `Enable or disable printing of Runtime.Log and Runtime.Notify messages emitted by
+the loaded program to the CLI output prefixed with 'LOG:' and 'NOTIFY:'. Disabled
+by default. Current mode is printed if no argument is given.
+
+Example:
+> verbose on`,
+		Action: handleVerbose,
+	},
+	{
+		Name:      "tee",
+		Usage:     "Mirror CLI output to the file",
+		UsageText: `tee <file>|off`,
+		Description: `Mirror all subsequent CLI output (including errors) to the specified file
+in addition to the terminal. The file is truncated if it exists. Use 'off' to
+stop mirroring and close the file.
+
+Example:
+> tee /path/to/session.log`,
+		Action: handleTee,
+	},
+	{
+		Name:      "record",
+		Usage:     "Record session transcript to the JSON-lines file",
+		UsageText: `record <file>|off`,
+		Description: `Record every subsequent command line along with its output and error (if
+any) to the specified file as a JSON object per line with 'command', 'output'
+and 'error' fields. The file is truncated if it exists, every record is written
+to it as soon as the command finishes. Use 'off' to stop recording and close
+the file. Recorded commands can be extracted and replayed with 'source'.
+
+Example:
+> record /path/to/session.jsonl`,
+		Action: handleRecord,
+	},
+	{
+		Name:      "export",
+		Usage:     "Export session transcript to the Markdown file",
+		UsageText: `export <file>`,
+		Description: `Write all command lines executed in this session (up to the last ` + strconv.Itoa(maxSessionEntries) + `)
+along with their outputs and errors followed by the current VM state and
+evaluation stack to the specified file in Markdown format. It's intended to be
+used for sharing reproductions, the file is truncated if it exists. Unlike
+'tee' and 'record' it doesn't need to be enabled beforehand.
+
+Example:
+> export /path/to/session.md`,
+		Action: handleExport,
+	},
+	{
+		Name:      "copy",
+		Usage:     "Write the loaded script, evaluation stack or disassembly to the file or CLI output",
+		UsageText: `copy script|stack|ops [<file>]`,
+		Description: `Write the current loaded script (hex-encoded), evaluation stack (JSON) or
+disassembly of the current loaded program to the specified file. The file is
+truncated if it exists. If no file is given, the result is written to the CLI
+output.
+
+Example:
+> copy script /path/to/script.hex`,
+		Action: handleCopy,
+	},
 	{
 		Name:      "storage",
 		Usage:     "Dump storage of the contract with the specified hash, address or ID as is at the current stage of script invocation",
@@ -415,7 +979,12 @@ Example:
 	},
 }
 
-var completer *readline.PrefixCompleter
+var (
+	completer *readline.PrefixCompleter
+
+	runtimeLogID   = interopnames.ToID([]byte(interopnames.SystemRuntimeLog))
+	checkWitnessID = interopnames.ToID([]byte(interopnames.SystemRuntimeCheckWitness))
+)
 
 func init() {
 	var pcItems []readline.PrefixCompleterInterface
@@ -436,6 +1005,7 @@ func init() {
 var (
 	ErrMissingParameter = errors.New("missing argument")
 	ErrInvalidParameter = errors.New("can't parse argument")
+	ErrUnknownCommand   = errors.New("unknown command")
 )
 
 // CLI object for interacting with the VM.
@@ -444,9 +1014,28 @@ type CLI struct {
 	shell *cli.App
 }
 
+// Options are additional VM CLI settings, see [NewWithOptions].
+type Options struct {
+	// ReadOnly makes programs to be loaded without [callflag.WriteStates], so
+	// any storage modification attempt (including non-safe native contract
+	// calls) fails.
+	ReadOnly bool
+	// Verbose enables verbose mode at startup (see 'verbose' command).
+	Verbose bool
+	// GasLimit is the GAS limit (in satoshi) used for loaded programs if
+	// --gas flag is not given, zero means no default.
+	GasLimit int64
+}
+
 // NewWithConfig returns new CLI instance using provided config and (optionally)
 // provided node config for state-backed VM.
 func NewWithConfig(printLogotype bool, onExit func(int), c *readline.Config, cfg config.Config) (*CLI, error) {
+	return NewWithOptions(printLogotype, onExit, c, cfg, Options{})
+}
+
+// NewWithOptions is the same as [NewWithConfig], but also allows to specify
+// additional CLI options.
+func NewWithOptions(printLogotype bool, onExit func(int), c *readline.Config, cfg config.Config, o Options) (*CLI, error) {
 	if c.AutoComplete == nil {
 		// Autocomplete commands/flags on TAB.
 		c.AutoComplete = completer
@@ -463,8 +1052,13 @@ func NewWithConfig(printLogotype bool, onExit func(int), c *readline.Config, cfg
 	ctl.HelpName = ""
 	ctl.UsageText = ""
 
-	ctl.Writer = l.Stdout()
-	ctl.ErrWriter = l.Stderr()
+	var (
+		session = new(sessionLog)
+		output  = new(outputSinks)
+	)
+	output.add(sessionKey, &session.output)
+	ctl.Writer = &sinkWriter{base: l.Stdout(), sinks: output}
+	ctl.ErrWriter = &sinkWriter{base: l.Stderr(), sinks: output}
 	ctl.Version = config.Version
 	ctl.Usage = "Official VM CLI for NeoGo"
 
@@ -504,6 +1098,7 @@ func NewWithConfig(printLogotype bool, onExit func(int), c *readline.Config, cfg
 	if err != nil {
 		return nil, cli.Exit(fmt.Errorf("could not initialize blockchain: %w", err), 1)
 	}
+
 	// Do not run chain, we need only state-related functionality from it.
 	ic, err := chain.GetTestVM(trigger.Application, nil, nil)
 	if err != nil {
@@ -519,11 +1114,22 @@ func NewWithConfig(printLogotype bool, onExit func(int), c *readline.Config, cfg
 		chainKey:            chain,
 		chainCfgKey:         cfg,
 		icKey:               ic,
-		contractStateKey:    new(state.ContractBase),
+		contractStateKey:    (*state.ContractBase)(nil), // Set by loadnef, loadgo and loaddeployed.
 		exitFuncKey:         exitF,
 		readlineInstanceKey: l,
 		printLogoKey:        printLogotype,
+		verboseKey:          o.Verbose,
+		readOnlyKey:         o.ReadOnly,
+		aliasesKey:          make(map[string][]string),
+		scriptSlotsKey:      make(map[string]*scriptSlot),
+		activeSlotKey:       defaultSlotName,
+		defaultGasKey:       o.GasLimit,
+		sessionKey:          session,
+		outputKey:           output,
+		goCompileCacheKey:   &goCompileCache{entries: make(map[string]*goCompileResult)},
 	}
+	// Add the default help command, so that it's known before the first Run.
+	vmcli.shell.Setup()
 	changePrompt(vmcli.shell)
 	return &vmcli, nil
 }
@@ -560,6 +1166,27 @@ func getPrintLogoFromContext(app *cli.App) bool {
 	return app.Metadata[printLogoKey].(bool)
 }
 
+func getVerboseFromContext(app *cli.App) bool {
+	return app.Metadata[verboseKey].(bool)
+}
+
+func getReadOnlyFromContext(app *cli.App) bool {
+	return app.Metadata[readOnlyKey].(bool)
+}
+
+// getCallFlags returns call flags programs are to be loaded with depending on
+// the read-only mode.
+func getCallFlags(app *cli.App) callflag.CallFlag {
+	if getReadOnlyFromContext(app) {
+		return callflag.All &^ callflag.WriteStates
+	}
+	return callflag.All
+}
+
+func getAliasesFromContext(app *cli.App) map[string][]string {
+	return app.Metadata[aliasesKey].(map[string][]string)
+}
+
 func setInteropContextInContext(app *cli.App, ic *interop.Context) {
 	app.Metadata[icKey] = ic
 }
@@ -579,6 +1206,11 @@ func checkVMIsReady(app *cli.App) bool {
 
 func handleExit(c *cli.Context) error {
 	finalizeInteropContext(c.App)
+	stopTee(c.App)
+	stopRecord(c.App)
+	for _, slot := range getScriptSlotsFromContext(c.App) {
+		slot.ic.Finalize()
+	}
 	l := getReadlineInstanceFromContext(c.App)
 	_ = l.Close()
 	exit := getExitFuncFromContext(c.App)
@@ -606,12 +1238,20 @@ func handleBreak(c *cli.Context) error {
 	if !checkVMIsReady(c.App) {
 		return nil
 	}
+	v := getVMFromContext(c.App)
+	if c.Args().First() == "clear" {
+		bps := v.Context().BreakPoints()
+		for _, bp := range bps {
+			v.RemoveBreakPoint(bp)
+		}
+		fmt.Fprintf(c.App.Writer, "%d breakpoint(s) cleared\n", len(bps))
+		return nil
+	}
 	n, err := getInstructionParameter(c)
 	if err != nil {
 		return err
 	}
 
-	v := getVMFromContext(c.App)
 	v.AddBreakPoint(n)
 	fmt.Fprintf(c.App.Writer, "breakpoint added at instruction %d\n", n)
 	return nil
@@ -676,9 +1316,16 @@ func handleXStack(c *cli.Context) error {
 	var stackDump string
 	switch c.Command.Name {
 	case "estack":
-		stackDump = v.DumpEStack()
+		if c.Bool(treeFlagFullName) {
+			stackDump = dumpEStackTree(v)
+		} else {
+			stackDump = dumpEStack(v)
+		}
 	case "istack":
-		stackDump = v.DumpIStack()
+		if !checkVMIsReady(c.App) {
+			return nil
+		}
+		stackDump = dumpIStack(v)
 	default:
 		return errors.New("unknown stack")
 	}
@@ -686,6 +1333,25 @@ func handleXStack(c *cli.Context) error {
 	return nil
 }
 
+func handleItemStats(c *cli.Context) error {
+	// Stack is still available after the script is finished.
+	v := getVMFromContext(c.App)
+	if v == nil || v.Estack().Len() == 0 {
+		return errors.New("evaluation stack is empty")
+	}
+	s := stackitem.GetStats(v.Estack().Peek(0).Item())
+	w := tabwriter.NewWriter(c.App.Writer, 0, 0, 4, ' ', 0)
+	for _, t := range slices.Sorted(maps.Keys(s.Types)) {
+		fmt.Fprintf(w, "%s:\t%d\n", t, s.Types[t])
+	}
+	fmt.Fprintf(w, "Total:\t%d (serialization limit: %d)\n", s.Total, stackitem.MaxSerialized)
+	fmt.Fprintf(w, "Max depth:\t%d\n", s.MaxDepth)
+	if s.Circular {
+		fmt.Fprintln(w, "Circular references found")
+	}
+	return w.Flush()
+}
+
 func handleSlots(c *cli.Context) error {
 	v := getVMFromContext(c.App)
 	vmCtx := v.Context()
@@ -711,7 +1377,72 @@ func dumpSlot(s *vm.Slot) string {
 	if s == nil {
 		return "[]"
 	}
-	b, _ := json.MarshalIndent(s, "", "    ")
+	return dumpItems(*s)
+}
+
+// dumpEStack returns JSON representation of the VM evaluation stack.
+func dumpEStack(v *vm.VM) string {
+	return dumpItems(v.Estack().ToArray())
+}
+
+// dumpIStack returns table representation of the VM invocation stack, the
+// current frame goes first.
+func dumpIStack(v *vm.VM) string {
+	var (
+		b      strings.Builder
+		w      = tabwriter.NewWriter(&b, 0, 0, 4, ' ', 0)
+		istack = v.Istack()
+	)
+	fmt.Fprintln(w, "INDEX\tIP\tSCRIPT HASH")
+	for i := range istack {
+		ctx := istack[len(istack)-1-i]
+		ip := "end"
+		if ctx.NextIP() < ctx.LenInstr() {
+			n, op := ctx.NextInstr()
+			ip = fmt.Sprintf("%d (%s)", n, op)
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\n", i, ip, ctx.ScriptHash().StringLE())
+	}
+	_ = w.Flush()
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// dumpEStackTree returns tree representation of the VM evaluation stack.
+func dumpEStackTree(v *vm.VM) string {
+	items := v.Estack().ToArray()
+	if len(items) == 0 {
+		return "evaluation stack is empty"
+	}
+	var b strings.Builder
+	for i := range items {
+		if i != 0 {
+			b.WriteByte('\n')
+		}
+		fmt.Fprintf(&b, "%d: %s", i, stackitem.ToTreeString(items[i]))
+	}
+	return b.String()
+}
+
+// dumpItems returns JSON representation of the given stack items limited by
+// dumpMaxDepth and dumpMaxSize. Items that can't be serialized are replaced
+// by the error description.
+func dumpItems(items []stackitem.Item) string {
+	arr := make([]json.RawMessage, len(items))
+	for i := range items {
+		if items[i] == nil {
+			continue // Uninitialized slot element.
+		}
+		data, err := stackitem.ToJSONWithTypesLimited(items[i], dumpMaxDepth, dumpMaxSize)
+		if err != nil {
+			msg := "error: " + err.Error()
+			if errors.Is(err, stackitem.ErrRecursive) {
+				msg = "error: circular reference"
+			}
+			data, _ = json.Marshal(msg)
+		}
+		arr[i] = data
+	}
+	b, _ := json.MarshalIndent(arr, "", "    ")
 	return string(b)
 }
 
@@ -732,6 +1463,8 @@ func prepareVM(c *cli.Context, tx *transaction.Transaction) error {
 		gas := c.Int64(gasFlagFullName)
 		v := getVMFromContext(c.App)
 		v.GasLimit = gas
+	} else if gas := c.App.Metadata[defaultGasKey].(int64); gas != 0 {
+		getVMFromContext(c.App).GasLimit = gas
 	}
 	return nil
 }
@@ -787,6 +1520,10 @@ func handleLoadNEF(c *cli.Context) error {
 	if err != nil {
 		return fmt.Errorf("failed to read manifest: %w", err)
 	}
+	err = checkManifestMatchesScript(nef.Script, m)
+	if err != nil {
+		return fmt.Errorf("%w: manifest does not match NEF: %w", ErrInvalidParameter, err)
+	}
 	var signers []transaction.Signer
 	if signersStartOffset != 0 && len(args) > signersStartOffset {
 		signers, err = cmdargs.ParseSigners(args[signersStartOffset:])
@@ -837,6 +1574,11 @@ func handleLoadBase64(c *cli.Context) error {
 			return fmt.Errorf("%w: %w", ErrInvalidParameter, err)
 		}
 	}
+	b, signers = appendToLoadedScript(c, b, signers)
+	err = checkScriptSize(c, b)
+	if err != nil {
+		return err
+	}
 	err = prepareVM(c, createFakeTransaction(b, signers))
 	if err != nil {
 		return err
@@ -847,6 +1589,33 @@ func handleLoadBase64(c *cli.Context) error {
 	return nil
 }
 
+// checkScriptSize returns an error if the given script exceeds the transaction
+// script size limit and --force flag is not set.
+func checkScriptSize(c *cli.Context, b []byte) error {
+	if len(b) > transaction.MaxScriptLength && !c.Bool(forceFlagFullName) {
+		return fmt.Errorf("%w: script size %d exceeds the limit of %d bytes, use --%s to load it anyway",
+			ErrInvalidParameter, len(b), transaction.MaxScriptLength, forceFlagFullName)
+	}
+	return nil
+}
+
+// appendToLoadedScript appends the given script to the currently loaded one if
+// --append flag is set. Signers of the currently loaded script are reused if no
+// new ones are provided.
+func appendToLoadedScript(c *cli.Context, b []byte, signers []transaction.Signer) ([]byte, []transaction.Signer) {
+	if !c.Bool(appendFlagFullName) {
+		return b, signers
+	}
+	tx := getInteropContextFromContext(c.App).Tx
+	if tx == nil {
+		return b, signers
+	}
+	if signers == nil {
+		signers = tx.Signers
+	}
+	return append(bytes.Clone(tx.Script), b...), signers
+}
+
 // createFakeTransaction creates fake transaction with prefilled script, VUB and signers.
 func createFakeTransaction(script []byte, signers []transaction.Signer) *transaction.Transaction {
 	return &transaction.Transaction{
@@ -855,17 +1624,49 @@ func createFakeTransaction(script []byte, signers []transaction.Signer) *transac
 	}
 }
 
-func handleLoadHex(c *cli.Context) error {
+func handlePushInt(c *cli.Context) error {
 	args := c.Args().Slice()
 	if len(args) < 1 {
-		return fmt.Errorf("%w: <string>", ErrMissingParameter)
+		return fmt.Errorf("%w: <value>", ErrMissingParameter)
 	}
-	b, err := hex.DecodeString(args[0])
+	n, ok := new(big.Int).SetString(args[0], 10)
+	if !ok {
+		return fmt.Errorf("%w: %s is not an integer", ErrInvalidParameter, args[0])
+	}
+	w := gio.NewBufBinWriter()
+	emit.BigInt(w.BinWriter, n)
+	if w.Err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidParameter, w.Err)
+	}
+	var (
+		script  []byte
+		signers []transaction.Signer
+	)
+	if tx := getInteropContextFromContext(c.App).Tx; tx != nil {
+		script = bytes.Clone(tx.Script)
+		signers = tx.Signers
+	}
+	err := prepareVM(c, createFakeTransaction(append(script, w.Bytes()...), signers))
 	if err != nil {
-		return fmt.Errorf("%w: %w", ErrInvalidParameter, err)
+		return err
 	}
-	var signers []transaction.Signer
-	if len(args) > 1 {
+	v := getVMFromContext(c.App)
+	fmt.Fprintf(c.App.Writer, "READY: loaded %d instructions\n", v.Context().LenInstr())
+	changePrompt(c.App)
+	return nil
+}
+
+func handleLoadHex(c *cli.Context) error {
+	args := c.Args().Slice()
+	if len(args) < 1 {
+		return fmt.Errorf("%w: <string>", ErrMissingParameter)
+	}
+	b, err := hex.DecodeString(args[0])
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidParameter, err)
+	}
+	var signers []transaction.Signer
+	if len(args) > 1 {
 		if args[1] != cmdargs.CosignersSeparator {
 			return fmt.Errorf("%w: `%s` was expected as the second parameter, got %s", ErrInvalidParameter, cmdargs.CosignersSeparator, args[1])
 		}
@@ -877,6 +1678,35 @@ func handleLoadHex(c *cli.Context) error {
 			return fmt.Errorf("%w: %w", ErrInvalidParameter, err)
 		}
 	}
+	b, signers = appendToLoadedScript(c, b, signers)
+	err = checkScriptSize(c, b)
+	if err != nil {
+		return err
+	}
+	err = prepareVM(c, createFakeTransaction(b, signers))
+	if err != nil {
+		return err
+	}
+	v := getVMFromContext(c.App)
+	fmt.Fprintf(c.App.Writer, "READY: loaded %d instructions\n", v.Context().LenInstr())
+	changePrompt(c.App)
+	return nil
+}
+
+func handleEmit(c *cli.Context) error {
+	args := c.Args().Slice()
+	if len(args) == 0 {
+		return fmt.Errorf("%w: <item>", ErrMissingParameter)
+	}
+	b, err := emitScript(getInteropContextFromContext(c.App), args)
+	if err != nil {
+		return err
+	}
+	b, signers := appendToLoadedScript(c, b, nil)
+	err = checkScriptSize(c, b)
+	if err != nil {
+		return err
+	}
 	err = prepareVM(c, createFakeTransaction(b, signers))
 	if err != nil {
 		return err
@@ -887,22 +1717,73 @@ func handleLoadHex(c *cli.Context) error {
 	return nil
 }
 
+// emitScript builds a script from the list of items (see emit command
+// description for the syntax).
+func emitScript(ic *interop.Context, items []string) ([]byte, error) {
+	w := gio.NewBufBinWriter()
+	for i := 0; i < len(items); i++ {
+		item := items[i]
+		switch item {
+		case "int", "string", "bytes", "syscall":
+			if i == len(items)-1 {
+				return nil, fmt.Errorf("%w: value expected after '%s'", ErrMissingParameter, item)
+			}
+			i++
+			arg := items[i]
+			switch item {
+			case "int":
+				n, ok := new(big.Int).SetString(arg, 10)
+				if !ok {
+					return nil, fmt.Errorf("%w: invalid integer %s", ErrInvalidParameter, arg)
+				}
+				emit.BigInt(w.BinWriter, n)
+			case "string":
+				emit.String(w.BinWriter, arg)
+			case "bytes":
+				b, err := hex.DecodeString(strings.TrimPrefix(arg, "0x"))
+				if err != nil {
+					return nil, fmt.Errorf("%w: %w", ErrInvalidParameter, err)
+				}
+				emit.Bytes(w.BinWriter, b)
+			default:
+				if ic.GetFunction(interopnames.ToID([]byte(arg))) == nil {
+					names := make([]string, 0, len(ic.Functions))
+					for _, f := range ic.Functions {
+						names = append(names, f.Name)
+					}
+					slices.Sort(names)
+					return nil, fmt.Errorf("%w: unknown syscall %s, valid ones are: %s", ErrInvalidParameter, arg, strings.Join(names, ", "))
+				}
+				emit.Syscall(w.BinWriter, arg)
+			}
+		default:
+			if hexStr, ok := strings.CutPrefix(item, "0x"); ok {
+				b, err := hex.DecodeString(hexStr)
+				if err != nil {
+					return nil, fmt.Errorf("%w: %w", ErrInvalidParameter, err)
+				}
+				w.WriteBytes(b)
+				continue
+			}
+			op, err := opcode.FromString(strings.ToUpper(item))
+			if err != nil {
+				return nil, fmt.Errorf("%w: unknown opcode %s, valid items are opcode names (see 'ops'), 0x<hex>, int <n>, string <s>, bytes <hex> and syscall <name>", ErrInvalidParameter, item)
+			}
+			emit.Opcodes(w.BinWriter, op)
+		}
+	}
+	return w.Bytes(), nil
+}
+
 func handleLoadGo(c *cli.Context) error {
 	args := c.Args().Slice()
 	if len(args) < 1 {
 		return fmt.Errorf("%w: <file>", ErrMissingParameter)
 	}
 
-	name := strings.TrimSuffix(args[0], ".go")
-	ne, di, err := compiler.CompileWithOptions(args[0], nil, &compiler.Options{Name: name})
-	if err != nil {
-		return fmt.Errorf("failed to compile: %w", err)
-	}
-
-	// Don't perform checks, just load.
-	m, err := di.ConvertToManifest(&compiler.Options{})
+	ne, m, err := compileGo(c.App, args[0])
 	if err != nil {
-		return fmt.Errorf("can't create manifest: %w", err)
+		return err
 	}
 	var signers []transaction.Signer
 	if len(args) > 1 {
@@ -939,6 +1820,100 @@ func handleLoadGo(c *cli.Context) error {
 	return nil
 }
 
+// maxGoCompileCacheEntries is the maximum number of 'loadgo' compilation
+// results kept, the oldest ones are dropped.
+const maxGoCompileCacheEntries = 16
+
+// goCompileCache holds 'loadgo' compilation results keyed by the absolute path
+// of the compiled file.
+type goCompileCache struct {
+	entries map[string]*goCompileResult
+	order   []string
+	hits    int
+}
+
+// goCompileResult is a single cached 'loadgo' compilation result along with
+// hashes of all source files the compiler loaded to produce it.
+type goCompileResult struct {
+	nef      *nef.File
+	manifest *manifest.Manifest
+	sources  map[string]util.Uint256
+}
+
+// get returns cached compilation result for the given file if none of its
+// sources were changed since then.
+func (c *goCompileCache) get(file string) (*goCompileResult, bool) {
+	res, ok := c.entries[file]
+	if !ok {
+		return nil, false
+	}
+	for name, h := range res.sources {
+		data, err := os.ReadFile(name)
+		if err != nil || hash.Sha256(data) != h {
+			return nil, false
+		}
+	}
+	return res, true
+}
+
+// add saves the compilation result for the given file dropping the oldest
+// one if the cache is full.
+func (c *goCompileCache) add(file string, res *goCompileResult) {
+	if _, ok := c.entries[file]; ok {
+		c.order = slices.DeleteFunc(c.order, func(f string) bool { return f == file })
+	} else if len(c.order) == maxGoCompileCacheEntries {
+		delete(c.entries, c.order[0])
+		c.order = slices.Delete(c.order, 0, 1)
+	}
+	c.entries[file] = res
+	c.order = append(c.order, file)
+}
+
+// hashSources returns hashes of the given files contents.
+func hashSources(files []string) (map[string]util.Uint256, error) {
+	res := make(map[string]util.Uint256, len(files))
+	for _, f := range files {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			return nil, err
+		}
+		res[f] = hash.Sha256(data)
+	}
+	return res, nil
+}
+
+// compileGo compiles the given Go file reusing the previous result if neither
+// the file nor any other source file used to compile it (including imported
+// packages) was changed since then.
+func compileGo(app *cli.App, file string) (*nef.File, *manifest.Manifest, error) {
+	cache := app.Metadata[goCompileCacheKey].(*goCompileCache)
+	abs, err := filepath.Abs(file)
+	if err != nil {
+		abs = file
+	}
+	if res, ok := cache.get(abs); ok {
+		cache.hits++
+		return res.nef, res.manifest, nil
+	}
+
+	name := strings.TrimSuffix(file, ".go")
+	ne, di, err := compiler.CompileWithOptions(file, nil, &compiler.Options{Name: name})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to compile: %w", err)
+	}
+
+	// Don't perform checks, just load.
+	m, err := di.ConvertToManifest(&compiler.Options{})
+	if err != nil {
+		return nil, nil, fmt.Errorf("can't create manifest: %w", err)
+	}
+	// Documents list all files loaded by the compiler.
+	if sources, err := hashSources(append([]string{abs}, di.Documents...)); err == nil {
+		cache.add(abs, &goCompileResult{nef: ne, manifest: m, sources: sources})
+	}
+	return ne, m, nil
+}
+
 func handleLoadTx(c *cli.Context) error {
 	args := c.Args().Slice()
 	if len(args) < 1 {
@@ -1028,7 +2003,7 @@ func handleLoadDeployed(c *cli.Context) error {
 	gasLimit := ic.VM.GasLimit
 	ic.ReuseVM(ic.VM) // clear previously loaded program and context.
 	ic.VM.GasLimit = gasLimit
-	ic.VM.LoadScriptWithHash(cs.NEF.Script, cs.Hash, callflag.All)
+	ic.VM.LoadScriptWithHash(cs.NEF.Script, cs.Hash, getCallFlags(c.App))
 	fmt.Fprintf(c.App.Writer, "READY: loaded %d instructions\n", ic.VM.Context().LenInstr())
 	setContractStateInContext(c.App, &cs.ContractBase)
 	changePrompt(c.App)
@@ -1044,6 +2019,137 @@ func handleReset(c *cli.Context) error {
 	return nil
 }
 
+func handleClear(c *cli.Context) error {
+	if !checkVMIsReady(c.App) {
+		return nil
+	}
+	if c.NArg() != 1 {
+		return fmt.Errorf("%w: estack|slots", ErrMissingParameter)
+	}
+	v := getVMFromContext(c.App)
+	switch c.Args().First() {
+	case "estack":
+		n := v.Estack().Len()
+		v.Estack().Clear()
+		fmt.Fprintf(c.App.Writer, "%d item(s) removed from evaluation stack\n", n)
+	case "slots":
+		n := v.ClearSlots()
+		fmt.Fprintf(c.App.Writer, "%d slot item(s) set to Null\n", n)
+	default:
+		return fmt.Errorf("%w: estack or slots expected, got %s", ErrInvalidParameter, c.Args().First())
+	}
+	return nil
+}
+
+// defaultSlotName is the name of the script slot that is active on start.
+const defaultSlotName = "main"
+
+// scriptSlot holds the state of an inactive named script slot.
+type scriptSlot struct {
+	ic       *interop.Context
+	contract *state.ContractBase
+}
+
+func getScriptSlotsFromContext(app *cli.App) map[string]*scriptSlot {
+	return app.Metadata[scriptSlotsKey].(map[string]*scriptSlot)
+}
+
+func getActiveSlotFromContext(app *cli.App) string {
+	return app.Metadata[activeSlotKey].(string)
+}
+
+// activateSlot stores the state of the currently active script slot and makes
+// the slot with the given name active. A new empty slot is created if there is
+// no slot with this name.
+func activateSlot(app *cli.App, name string) error {
+	var (
+		slots = getScriptSlotsFromContext(app)
+		slot  = slots[name]
+	)
+	if slot == nil {
+		ic, err := getChainFromContext(app).GetTestVM(trigger.Application, nil, nil)
+		if err != nil {
+			return fmt.Errorf("failed to create VM: %w", err)
+		}
+		slot = &scriptSlot{ic: ic}
+	}
+	slots[getActiveSlotFromContext(app)] = &scriptSlot{
+		ic:       getInteropContextFromContext(app),
+		contract: getContractStateFromContext(app),
+	}
+	delete(slots, name)
+	setInteropContextInContext(app, slot.ic)
+	setContractStateInContext(app, slot.contract)
+	app.Metadata[activeSlotKey] = name
+	return nil
+}
+
+func handleLoadSlot(c *cli.Context) error {
+	args := c.Args().Slice()
+	if len(args) < 2 {
+		return fmt.Errorf("%w: <name> <file>", ErrMissingParameter)
+	}
+	script, err := readScriptFile(args[1])
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidParameter, err)
+	}
+	if args[0] != getActiveSlotFromContext(c.App) {
+		err = activateSlot(c.App, args[0])
+		if err != nil {
+			return err
+		}
+	}
+	err = prepareVM(c, createFakeTransaction(script, nil))
+	if err != nil {
+		return err
+	}
+	v := getVMFromContext(c.App)
+	fmt.Fprintf(c.App.Writer, "READY: loaded %d instructions into slot '%s'\n", v.Context().LenInstr(), args[0])
+	changePrompt(c.App)
+	return nil
+}
+
+func handleSwitch(c *cli.Context) error {
+	if !c.Args().Present() {
+		return fmt.Errorf("%w: <name>", ErrMissingParameter)
+	}
+	name := c.Args().First()
+	if name == getActiveSlotFromContext(c.App) {
+		fmt.Fprintf(c.App.Writer, "slot '%s' is already active\n", name)
+		return nil
+	}
+	if _, ok := getScriptSlotsFromContext(c.App)[name]; !ok {
+		return fmt.Errorf("%w: unknown slot '%s'", ErrInvalidParameter, name)
+	}
+	err := activateSlot(c.App, name)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(c.App.Writer, "switched to slot '%s'\n", name)
+	changePrompt(c.App)
+	return nil
+}
+
+func handleListScriptSlots(c *cli.Context) error {
+	var (
+		active = getActiveSlotFromContext(c.App)
+		slots  = maps.Clone(getScriptSlotsFromContext(c.App))
+		w      = tabwriter.NewWriter(c.App.Writer, 0, 0, 4, ' ', 0)
+	)
+	slots[active] = &scriptSlot{ic: getInteropContextFromContext(c.App)}
+	for _, name := range slices.Sorted(maps.Keys(slots)) {
+		var mark, program = " ", "no program loaded"
+		if name == active {
+			mark = "*"
+		}
+		if tx := slots[name].ic.Tx; tx != nil {
+			program = fmt.Sprintf("%d instructions", len(tx.Script))
+		}
+		fmt.Fprintf(w, "%s %s\t%s\n", mark, name, program)
+	}
+	return w.Flush()
+}
+
 // finalizeInteropContext calls finalizer for the current interop context.
 func finalizeInteropContext(app *cli.App) {
 	ic := getInteropContextFromContext(app)
@@ -1078,7 +2184,7 @@ func resetInteropContext(app *cli.App, tx *transaction.Transaction, height ...ui
 		}
 	}
 	if tx != nil {
-		newIc.VM.LoadWithFlags(tx.Script, callflag.All)
+		newIc.VM.LoadWithFlags(tx.Script, getCallFlags(app))
 	}
 
 	setInteropContextInContext(app, newIc)
@@ -1101,6 +2207,95 @@ func resetState(app *cli.App, tx *transaction.Transaction, height ...uint32) err
 	return nil
 }
 
+func handleValidateNEF(c *cli.Context) error {
+	args := c.Args().Slice()
+	if len(args) < 1 {
+		return fmt.Errorf("%w: <file> is required", ErrMissingParameter)
+	}
+	if len(args) > 2 {
+		return fmt.Errorf("%w: too many arguments", ErrInvalidParameter)
+	}
+	var (
+		nefFile      = args[0]
+		manifestFile = strings.TrimSuffix(nefFile, ".nef") + ".manifest.json"
+		nefData      *nef.File
+		m            *manifest.Manifest
+		issues       []string
+	)
+	if len(args) == 2 {
+		manifestFile = args[1]
+	}
+
+	b, err := os.ReadFile(nefFile)
+	if err == nil {
+		var f nef.File
+		f, err = nef.FileFromBytes(b)
+		nefData = &f
+	}
+	if err != nil {
+		nefData = nil
+		issues = append(issues, fmt.Sprintf("NEF: %s", err))
+	}
+
+	b, err = os.ReadFile(manifestFile)
+	if err == nil {
+		m = new(manifest.Manifest)
+		err = json.Unmarshal(b, m)
+	}
+	if err != nil {
+		m = nil
+		issues = append(issues, fmt.Sprintf("manifest: %s", err))
+	} else if err = m.ABI.IsValid(); err != nil {
+		issues = append(issues, fmt.Sprintf("manifest: ABI: %s", err))
+	}
+
+	if nefData != nil {
+		if err = vm.IsScriptCorrect(nefData.Script, nil); err != nil {
+			issues = append(issues, fmt.Sprintf("script: %s", err))
+		}
+		if m != nil {
+			if err = checkManifestMatchesScript(nefData.Script, m); err != nil {
+				issues = append(issues, err.Error())
+			}
+		}
+	}
+
+	if len(issues) == 0 {
+		fmt.Fprintln(c.App.Writer, "PASS: NEF file and manifest are valid")
+		return nil
+	}
+	fmt.Fprintf(c.App.Writer, "FAIL: %d issue(s) found:\n", len(issues))
+	for _, issue := range issues {
+		fmt.Fprintf(c.App.Writer, "  - %s\n", issue)
+	}
+	return nil
+}
+
+// checkManifestMatchesScript checks that all manifest methods point to
+// instruction boundaries of the given script. Script correctness is not
+// checked, instructions after the first one that can't be decoded are ignored.
+func checkManifestMatchesScript(script []byte, m *manifest.Manifest) error {
+	var (
+		ctx    = vm.NewContext(script)
+		instrs = bitfield.New(len(script))
+	)
+	for ctx.NextIP() < ctx.LenInstr() {
+		if _, _, err := ctx.Next(); err != nil {
+			break
+		}
+		instrs.Set(ctx.IP())
+	}
+	for _, md := range m.ABI.Methods {
+		if md.Offset < 0 || md.Offset >= len(script) {
+			return fmt.Errorf("method %s/%d: offset %d is out of the script range", md.Name, len(md.Parameters), md.Offset)
+		}
+		if !instrs.IsSet(md.Offset) {
+			return fmt.Errorf("method %s/%d: offset %d is not an instruction boundary", md.Name, len(md.Parameters), md.Offset)
+		}
+	}
+	return nil
+}
+
 func getManifestFromFile(name string) (*manifest.Manifest, error) {
 	bs, err := os.ReadFile(name)
 	if err != nil {
@@ -1127,7 +2322,11 @@ func handleRun(c *cli.Context) error {
 			hasRet     bool
 		)
 
-		_, scParams, err := cmdargs.ParseParams(args[1:], true)
+		fileArgs, err := expandFileArgs(args[1:])
+		if err != nil {
+			return err
+		}
+		_, scParams, err := cmdargs.ParseParams(fileArgs, true)
 		if err != nil {
 			return fmt.Errorf("%w: %w", ErrInvalidParameter, err)
 		}
@@ -1146,6 +2345,10 @@ func handleRun(c *cli.Context) error {
 			if md == nil {
 				return fmt.Errorf("%w: method not found", ErrInvalidParameter)
 			}
+			if isPaused(v) && !c.Bool(restartFlagFullName) {
+				return fmt.Errorf("execution is paused at instruction %d, use 'cont' to continue it or 'run --restart %s' to invoke the method from scratch",
+					v.Context().NextIP(), strings.Join(args, " "))
+			}
 			hasRet = md.ReturnType != smartcontract.VoidType
 			offset = md.Offset
 			var initOff = -1
@@ -1160,7 +2363,7 @@ func handleRun(c *cli.Context) error {
 			breaks := v.Context().BreakPoints() // We ensure that there's a context loaded.
 			ic.ReuseVM(v)
 			v.GasLimit = gasLimit
-			v.LoadNEFMethod(&cs.NEF, &cs.Manifest, util.Uint160{}, cs.Hash, callflag.All, hasRet, offset, initOff, nil)
+			v.LoadNEFMethod(&cs.NEF, &cs.Manifest, util.Uint160{}, cs.Hash, getCallFlags(c.App), hasRet, offset, initOff, nil)
 			for _, bp := range breaks {
 				v.AddBreakPoint(bp)
 			}
@@ -1169,15 +2372,58 @@ func handleRun(c *cli.Context) error {
 			v.Estack().PushVal(params[i])
 		}
 	}
-	runVMWithHandling(c)
+	runVMWithHandling(c, 0)
 	changePrompt(c.App)
 	return nil
 }
 
+// expandFileArgs replaces '@<file>[:hex|:base64]' parameters with 'bytes'
+// ones holding (decoded) file contents, other parameters are left as is.
+func expandFileArgs(args []string) ([]string, error) {
+	var res = make([]string, len(args))
+	for i, arg := range args {
+		path, ok := strings.CutPrefix(arg, "@")
+		if !ok {
+			res[i] = arg
+			continue
+		}
+		var enc string
+		if j := strings.LastIndexByte(path, ':'); j >= 0 && (path[j+1:] == "hex" || path[j+1:] == "base64") {
+			path, enc = path[:j], path[j+1:]
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("%w: failed to read parameter #%d from file: %w", ErrInvalidParameter, i, err)
+		}
+		switch enc {
+		case "hex":
+			data, err = hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(string(data)), "0x"))
+		case "base64":
+			data, err = base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: failed to decode %s parameter #%d from file %s: %w", ErrInvalidParameter, enc, i, path, err)
+		}
+		res[i] = "bytes:" + hex.EncodeToString(data)
+	}
+	return res, nil
+}
+
+// isPaused returns true if execution of the loaded program has started, but
+// hasn't finished yet.
+func isPaused(v *vm.VM) bool {
+	return v.Ready() && !v.HasFailed() && (v.AtBreakpoint() || v.GasConsumed() > 0)
+}
+
 // runVMWithHandling runs VM with handling errors and additional state messages.
-func runVMWithHandling(c *cli.Context) {
+// The first skipBreaks breakpoint hits are passed silently.
+func runVMWithHandling(c *cli.Context, skipBreaks int) {
+	setSyscallHandler(c.App)
 	v := getVMFromContext(c.App)
 	err := v.Run()
+	for ; err == nil && skipBreaks > 0 && v.AtBreakpoint(); skipBreaks-- {
+		err = v.Run()
+	}
 	if err != nil {
 		writeErr(c.App.ErrWriter, err)
 	}
@@ -1191,13 +2437,16 @@ func runVMWithHandling(c *cli.Context) {
 		message = "" // the error will be printed on return
 		dumpNtf = true
 	case v.HasHalted():
-		message = v.DumpEStack()
+		message = dumpEStack(v)
 		dumpNtf = true
 	case v.AtBreakpoint():
 		ctx := v.Context()
 		if ctx.NextIP() < ctx.LenInstr() {
 			i, op := ctx.NextInstr()
 			message = fmt.Sprintf("at breakpoint %d (%s)", i, op)
+			if param := ctx.NextInstrParameter(); param != "" {
+				message += " " + param
+			}
 		} else {
 			message = "execution has finished"
 		}
@@ -1218,15 +2467,6 @@ func runVMWithHandling(c *cli.Context) {
 }
 
 func handleCont(c *cli.Context) error {
-	if !checkVMIsReady(c.App) {
-		return nil
-	}
-	runVMWithHandling(c)
-	changePrompt(c.App)
-	return nil
-}
-
-func handleStep(c *cli.Context) error {
 	var (
 		n   = 1
 		err error
@@ -1235,77 +2475,1038 @@ func handleStep(c *cli.Context) error {
 	if !checkVMIsReady(c.App) {
 		return nil
 	}
-	v := getVMFromContext(c.App)
 	args := c.Args().Slice()
 	if len(args) > 0 {
 		n, err = strconv.Atoi(args[0])
 		if err != nil {
 			return fmt.Errorf("%w: %w", ErrInvalidParameter, err)
 		}
+		if n < 1 {
+			return fmt.Errorf("%w: breakpoint hits number should be positive", ErrInvalidParameter)
+		}
 	}
-	v.AddBreakPointRel(n)
-	runVMWithHandling(c)
+	runVMWithHandling(c, n-1)
 	changePrompt(c.App)
 	return nil
 }
 
-func handleStepInto(c *cli.Context) error {
-	return handleStepType(c, "into")
-}
-
-func handleStepOut(c *cli.Context) error {
-	return handleStepType(c, "out")
-}
-
-func handleStepOver(c *cli.Context) error {
-	return handleStepType(c, "over")
-}
-
-func handleStepType(c *cli.Context, stepType string) error {
+func handleUntil(c *cli.Context) error {
 	if !checkVMIsReady(c.App) {
 		return nil
 	}
-	v := getVMFromContext(c.App)
-	var err error
-	switch stepType {
-	case "into":
-		err = v.StepInto()
-	case "out":
-		err = v.StepOut()
-	case "over":
-		err = v.StepOver()
-	}
+	n, err := getInstructionParameter(c)
 	if err != nil {
 		return err
 	}
-	_ = handleIP(c)
-	changePrompt(c.App)
-	return nil
-}
-
-func handleOps(c *cli.Context) error {
-	if !checkVMIsReady(c.App) {
-		return nil
-	}
 	v := getVMFromContext(c.App)
-	out := bytes.NewBuffer(nil)
-	v.PrintOps(out)
-	fmt.Fprintln(c.App.Writer, out.String())
+	ctx := v.Context()
+	if n < 0 || n >= ctx.LenInstr() {
+		return fmt.Errorf("%w: instruction %d is out of the script range", ErrInvalidParameter, n)
+	}
+	setSyscallHandler(c.App)
+	for {
+		err = v.StepInto()
+		if err != nil || v.HasStopped() {
+			break
+		}
+		cur := v.Context()
+		if (cur == ctx && cur.NextIP() == n) || slices.Contains(cur.BreakPoints(), cur.NextIP()) {
+			break
+		}
+	}
+	switch {
+	case err != nil:
+		writeErr(c.App.ErrWriter, err)
+		dumpFault(c.App)
+	case v.HasHalted():
+		fmt.Fprintln(c.App.Writer, dumpEStack(v))
+	default:
+		_ = handleIP(c)
+	}
+	changePrompt(c.App)
+	return nil
+}
+
+func handleDryRun(c *cli.Context) error {
+	if !checkVMIsReady(c.App) {
+		return nil
+	}
+	var (
+		assume = c.Bool(assumeFlagFullName)
+		script []byte
+		tx     = getInteropContextFromContext(c.App).Tx
+	)
+	if tx != nil {
+		script = bytes.Clone(tx.Script)
+	} else {
+		script = bytes.Clone(getVMFromContext(c.App).Istack()[0].Program()) // The entry script.
+		tx = createFakeTransaction(script, nil)
+	}
+	ic, err := getChainFromContext(c.App).GetTestVM(trigger.Application, tx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create VM: %w", err)
+	}
+	defer ic.Finalize()
+
+	type witnessCheck struct {
+		hash util.Uint160
+		ok   bool
+	}
+	var checks []witnessCheck
+	ic.VM.SyscallHandler = func(v *vm.VM, id uint32) error {
+		if id != checkWitnessID || v.Estack().Len() == 0 {
+			return ic.SyscallHandler(v, id)
+		}
+		h, err := getWitnessHash(v.Estack().Peek(0).Item())
+		if err != nil {
+			return ic.SyscallHandler(v, id) // Faults with a proper error.
+		}
+		i := slices.IndexFunc(checks, func(c witnessCheck) bool { return c.hash.Equals(h) })
+		if i < 0 {
+			i = len(checks)
+			checks = append(checks, witnessCheck{hash: h})
+		}
+		n := v.Estack().Len()
+		err = ic.SyscallHandler(v, id)
+		if err != nil {
+			if v.Estack().Len() == n {
+				return err // Failed before checking the witness.
+			}
+			// The witness can't be checked (like when there are no
+			// signers at all), so it's missing.
+			v.Estack().PushItem(stackitem.Bool(false))
+		}
+		ok := v.Estack().Peek(0).Bool()
+		checks[i].ok = checks[i].ok || ok
+		if !ok && assume {
+			v.Estack().Pop()
+			v.Estack().PushItem(stackitem.Bool(true))
+		}
+		return nil
+	}
+	ic.VM.LoadWithFlags(script, getCallFlags(c.App))
+	err = ic.VM.Run()
+
+	w := c.App.Writer
+	if len(checks) == 0 {
+		fmt.Fprintln(w, "no witnesses required")
+	} else {
+		fmt.Fprintln(w, "Required witnesses:")
+		for _, wc := range checks {
+			status := "signed"
+			if !wc.ok {
+				status = "missing"
+			}
+			fmt.Fprintf(w, "  %s (%s): %s\n", wc.hash.StringLE(), address.Uint160ToString(wc.hash), status)
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(w, "Dry run FAULT: %s\n", err)
+	}
+	return nil
+}
+
+// getWitnessHash returns the script hash System.Runtime.CheckWitness argument
+// refers to, it's either a hash itself or a public key.
+func getWitnessHash(item stackitem.Item) (util.Uint160, error) {
+	b, err := item.TryBytes()
+	if err != nil {
+		return util.Uint160{}, err
+	}
+	if h, err := util.Uint160DecodeBytesBE(b); err == nil {
+		return h, nil
+	}
+	key, err := keys.NewPublicKeyFromBytes(b, elliptic.P256())
+	if err != nil {
+		return util.Uint160{}, err
+	}
+	return key.GetScriptHash(), nil
+}
+
+func handleStepUntilFault(c *cli.Context) error {
+	if !checkVMIsReady(c.App) {
+		return nil
+	}
+	var (
+		v     = getVMFromContext(c.App)
+		steps int
+		err   error
+	)
+	setSyscallHandler(c.App)
+	for err == nil && !v.HasStopped() {
+		err = v.StepInto()
+		steps++
+	}
+	if err != nil || v.HasFailed() {
+		if err != nil {
+			writeErr(c.App.ErrWriter, err)
+		}
+		fmt.Fprintf(c.App.Writer, "FAULT after %d step(s)\n", steps)
+		dumpFault(c.App)
+	} else {
+		fmt.Fprintf(c.App.Writer, "HALT after %d step(s)\n", steps)
+		fmt.Fprintln(c.App.Writer, dumpEStack(v))
+	}
+	changePrompt(c.App)
+	return nil
+}
+
+func handleStep(c *cli.Context) error {
+	var (
+		n   = 1
+		err error
+	)
+
+	if !checkVMIsReady(c.App) {
+		return nil
+	}
+	v := getVMFromContext(c.App)
+	args := c.Args().Slice()
+	if len(args) > 0 {
+		n, err = strconv.Atoi(args[0])
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidParameter, err)
+		}
+	}
+	if c.Bool(intoSyscallFlagName) {
+		return stepIntoSyscall(c, n)
+	}
+	v.AddBreakPointRel(n)
+	runVMWithHandling(c, 0)
+	if v.HasFailed() {
+		dumpFault(c.App)
+	}
+	changePrompt(c.App)
+	return nil
+}
+
+// stepIntoSyscall executes up to n instructions stopping before any SYSCALL
+// except the one VM is paused at.
+func stepIntoSyscall(c *cli.Context, n int) error {
+	setSyscallHandler(c.App)
+	v := getVMFromContext(c.App)
+	for i := 0; i < n && !v.HasStopped() && v.Context() != nil; i++ {
+		if _, op := v.Context().NextInstr(); i > 0 && op == opcode.SYSCALL {
+			break
+		}
+		if err := v.StepInto(); err != nil {
+			if !v.HasFailed() {
+				return err
+			}
+			writeErr(c.App.ErrWriter, err)
+			dumpFault(c.App)
+			changePrompt(c.App)
+			return nil
+		}
+	}
+	if !v.Ready() {
+		// The last context is unloaded, so print the result the same way run does.
+		fmt.Fprintln(c.App.Writer, dumpEStack(v))
+		changePrompt(c.App)
+		return nil
+	}
+	_ = handleIP(c)
+	if ctx := v.Context(); ctx.NextIP() < ctx.LenInstr() {
+		if _, op := ctx.NextInstr(); op == opcode.SYSCALL {
+			fmt.Fprintf(c.App.Writer, "paused before SYSCALL %s\n", ctx.NextInstrParameter())
+			fmt.Fprintln(c.App.Writer, dumpSyscallArgs(v))
+		}
+	}
+	changePrompt(c.App)
+	return nil
+}
+
+// dumpSyscallArgs returns up to maxSyscallArgs topmost estack items, the
+// first one is the top of the stack.
+func dumpSyscallArgs(v *vm.VM) string {
+	estack := v.Estack()
+	if estack.Len() == 0 {
+		return "evaluation stack is empty"
+	}
+	var b strings.Builder
+	b.WriteString("Interop arguments (topmost estack items):")
+	for i := range min(estack.Len(), maxSyscallArgs) {
+		fmt.Fprintf(&b, "\n%d: %s", i, stackitem.ToTreeString(estack.Peek(i).Item()))
+	}
+	return b.String()
+}
+
+func handleStepInto(c *cli.Context) error {
+	return handleStepType(c, "into")
+}
+
+func handleStepOut(c *cli.Context) error {
+	return handleStepType(c, "out")
+}
+
+func handleStepOver(c *cli.Context) error {
+	return handleStepType(c, "over")
+}
+
+func handleStepType(c *cli.Context, stepType string) error {
+	if !checkVMIsReady(c.App) {
+		return nil
+	}
+	setSyscallHandler(c.App)
+	v := getVMFromContext(c.App)
+	var err error
+	switch stepType {
+	case "into":
+		err = v.StepInto()
+	case "out":
+		err = v.StepOut()
+	case "over":
+		err = v.StepOver()
+	}
+	if err != nil {
+		if !v.HasFailed() {
+			return err
+		}
+		writeErr(c.App.ErrWriter, err)
+		dumpFault(c.App)
+		changePrompt(c.App)
+		return nil
+	}
+	_ = handleIP(c)
+	changePrompt(c.App)
+	return nil
+}
+
+// dumpFault prints the index and opcode of the instruction that has put the VM
+// into the FAULT state. The VM itself is left intact, so that its stacks and
+// slots can still be inspected.
+func dumpFault(app *cli.App) {
+	v := getVMFromContext(app)
+	message := "FAULT"
+	if ctx := v.Context(); ctx != nil && ctx.IP() >= 0 && ctx.IP() < ctx.LenInstr() {
+		i, op := ctx.CurrInstr()
+		message = fmt.Sprintf("FAULT at instruction %d (%s)", i, op)
+	}
+	fmt.Fprintln(app.Writer, message)
+}
+
+func handleOps(c *cli.Context) error {
+	if !checkVMIsReady(c.App) {
+		return nil
+	}
+	var (
+		args  = c.Args().Slice()
+		v     = getVMFromContext(c.App)
+		start int
+		count int
+		err   error
+	)
+	if len(args) > 0 {
+		start, err = strconv.Atoi(args[0])
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidParameter, err)
+		}
+		if start < 0 || start >= v.Context().LenInstr() {
+			return fmt.Errorf("%w: instruction %d is out of the script range", ErrInvalidParameter, start)
+		}
+	}
+	if len(args) > 1 {
+		count, err = strconv.Atoi(args[1])
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidParameter, err)
+		}
+		if count <= 0 {
+			return fmt.Errorf("%w: count must be positive", ErrInvalidParameter)
+		}
+	}
+	out := bytes.NewBuffer(nil)
+	v.PrintOpsRange(out, start, count)
+	fmt.Fprintln(c.App.Writer, out.String())
+	return nil
+}
+
+func handleFind(c *cli.Context) error {
+	if !checkVMIsReady(c.App) {
+		return nil
+	}
+	var (
+		args      = c.Args().Slice()
+		op        opcode.Opcode
+		id        uint32
+		isSyscall bool
+		err       error
+	)
+	switch {
+	case len(args) == 0:
+		return fmt.Errorf("%w: <opcode>", ErrMissingParameter)
+	case args[0] == "syscall":
+		if len(args) < 2 {
+			return fmt.Errorf("%w: <name>", ErrMissingParameter)
+		}
+		id = interopnames.ToID([]byte(args[1]))
+		if name, err := interopnames.FromID(id); err != nil || name != args[1] {
+			return fmt.Errorf("%w: unknown syscall %s", ErrInvalidParameter, args[1])
+		}
+		op, isSyscall = opcode.SYSCALL, true
+	default:
+		op, err = opcode.FromString(strings.ToUpper(args[0]))
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidParameter, err)
+		}
+	}
+
+	var (
+		v       = getVMFromContext(c.App)
+		ctx     = vm.NewContext(v.Context().Program())
+		indices []string
+	)
+	for ctx.NextIP() < ctx.LenInstr() {
+		instr, param, err := ctx.Next()
+		if err != nil {
+			break
+		}
+		if instr == op && (!isSyscall || vm.GetInteropID(param) == id) {
+			indices = append(indices, strconv.Itoa(ctx.IP()))
+		}
+	}
+	if len(indices) == 0 {
+		fmt.Fprintln(c.App.Writer, "no instructions found")
+		return nil
+	}
+	fmt.Fprintf(c.App.Writer, "found at: %s\n", strings.Join(indices, ", "))
+	return nil
+}
+
+func handleFindXref(c *cli.Context) error {
+	if !checkVMIsReady(c.App) {
+		return nil
+	}
+	n, err := getInstructionParameter(c)
+	if err != nil {
+		return err
+	}
+	var (
+		v    = getVMFromContext(c.App)
+		ctx  = vm.NewContext(v.Context().Program())
+		refs []string
+	)
+	if n < 0 || n >= ctx.LenInstr() {
+		return fmt.Errorf("%w: instruction %d is out of the script range", ErrInvalidParameter, n)
+	}
+	for ctx.NextIP() < ctx.LenInstr() {
+		op, param, err := ctx.Next()
+		if err != nil {
+			break
+		}
+		if slices.Contains(getInstructionTargets(ctx.IP(), op, param), n) {
+			refs = append(refs, fmt.Sprintf("%d (%s)", ctx.IP(), op))
+		}
+	}
+	if len(refs) == 0 {
+		fmt.Fprintln(c.App.Writer, "no references found")
+		return nil
+	}
+	fmt.Fprintf(c.App.Writer, "referenced at: %s\n", strings.Join(refs, ", "))
+	return nil
+}
+
+// getInstructionTargets returns indices of instructions statically referenced
+// by the instruction located at ip.
+func getInstructionTargets(ip int, op opcode.Opcode, param []byte) []int {
+	offset := func(p []byte) int {
+		if len(p) == 1 {
+			return ip + int(int8(p[0]))
+		}
+		return ip + int(int32(binary.LittleEndian.Uint32(p)))
+	}
+	switch {
+	case op >= opcode.JMP && op <= opcode.CALLL, op == opcode.PUSHA,
+		op == opcode.ENDTRY, op == opcode.ENDTRYL:
+		return []int{offset(param)}
+	case op == opcode.TRY, op == opcode.TRYL:
+		var (
+			half = len(param) / 2
+			res  []int
+		)
+		// Zero offset means there is no catch/finally block.
+		for _, p := range [][]byte{param[:half], param[half:]} {
+			if dst := offset(p); dst != ip {
+				res = append(res, dst)
+			}
+		}
+		return res
+	}
+	return nil
+}
+
+// scriptInstruction is a single decoded script instruction.
+type scriptInstruction struct {
+	ip    int
+	op    opcode.Opcode
+	param []byte
+}
+
+func (i scriptInstruction) String() string {
+	if i.param == nil {
+		return fmt.Sprintf("%d %s", i.ip, i.op)
+	}
+	return fmt.Sprintf("%d %s %x", i.ip, i.op, i.param)
+}
+
+func (i scriptInstruction) equals(other scriptInstruction) bool {
+	return i.op == other.op && bytes.Equal(i.param, other.param)
+}
+
+// readScriptFile returns script from the given NEF, hex or base64 file.
+func readScriptFile(path string) ([]byte, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if f, err := nef.FileFromBytes(b); err == nil {
+		return f.Script, nil
+	}
+	str := strings.TrimSpace(string(b))
+	if script, err := hex.DecodeString(str); err == nil {
+		return script, nil
+	}
+	if script, err := base64.StdEncoding.DecodeString(str); err == nil {
+		return script, nil
+	}
+	return nil, errors.New("neither NEF, nor hex, nor base64 script")
+}
+
+// disassemble returns all instructions of the given script.
+func disassemble(script []byte) ([]scriptInstruction, error) {
+	var (
+		ctx = vm.NewContext(script)
+		res []scriptInstruction
+	)
+	for ctx.NextIP() < ctx.LenInstr() {
+		op, param, err := ctx.Next()
+		if err != nil {
+			return nil, fmt.Errorf("bad instruction at %d: %w", ctx.IP(), err)
+		}
+		res = append(res, scriptInstruction{ip: ctx.IP(), op: op, param: param})
+	}
+	return res, nil
+}
+
+func handleDiff(c *cli.Context) error {
+	args := c.Args().Slice()
+	if len(args) < 2 {
+		return fmt.Errorf("%w: <file1> <file2>", ErrMissingParameter)
+	}
+	var instrs [2][]scriptInstruction
+	for i := range instrs {
+		script, err := readScriptFile(args[i])
+		if err != nil {
+			return fmt.Errorf("%w: %s: %w", ErrInvalidParameter, args[i], err)
+		}
+		instrs[i], err = disassemble(script)
+		if err != nil {
+			return fmt.Errorf("%w: %s: %w", ErrInvalidParameter, args[i], err)
+		}
+	}
+	// Common prefix and suffix are skipped, the rest is compared pairwise,
+	// that's enough to properly show a single inserted or changed block.
+	a, b := instrs[0], instrs[1]
+	for len(a) > 0 && len(b) > 0 && a[0].equals(b[0]) {
+		a, b = a[1:], b[1:]
+	}
+	for len(a) > 0 && len(b) > 0 && a[len(a)-1].equals(b[len(b)-1]) {
+		a, b = a[:len(a)-1], b[:len(b)-1]
+	}
+	if len(a) == 0 && len(b) == 0 {
+		fmt.Fprintln(c.App.Writer, "scripts are identical")
+		return nil
+	}
+	for i := range max(len(a), len(b)) {
+		switch {
+		case i >= len(a):
+			fmt.Fprintf(c.App.Writer, "+ %s\n", b[i])
+		case i >= len(b):
+			fmt.Fprintf(c.App.Writer, "- %s\n", a[i])
+		default:
+			fmt.Fprintf(c.App.Writer, "~ %s => %s\n", a[i], b[i])
+		}
+	}
+	return nil
+}
+
+func handleInterop(c *cli.Context) error {
+	args := c.Args().Slice()
+	if len(args) == 0 {
+		return fmt.Errorf("%w: <command>", ErrMissingParameter)
+	}
+	if args[0] != "price" {
+		return fmt.Errorf("%w: unknown command %s", ErrInvalidParameter, args[0])
+	}
+	if len(args) < 2 {
+		return fmt.Errorf("%w: <name>", ErrMissingParameter)
+	}
+	var (
+		name = args[1]
+		id   = interopnames.ToID([]byte(name))
+		ic   = getInteropContextFromContext(c.App)
+	)
+	if n, err := interopnames.FromID(id); err != nil || n != name {
+		return fmt.Errorf("%w: unknown interop %s", ErrInvalidParameter, name)
+	}
+	f := ic.GetFunction(id)
+	if f == nil {
+		return fmt.Errorf("%w: interop %s is not available", ErrInvalidParameter, name)
+	}
+	price := f.Price * ic.BaseExecFee()
+	fmt.Fprintf(c.App.Writer, "%s price: %d (%s GAS)\n", name, price, fixedn.Fixed8(price))
+	return nil
+}
+
+func handleCallGraph(c *cli.Context) error {
+	if !checkVMIsReady(c.App) {
+		return nil
+	}
+	var (
+		v     = getVMFromContext(c.App)
+		ctx   = vm.NewContext(v.Context().Program())
+		dot   = c.Bool(dotFlagFullName)
+		lines []string
+	)
+	for ctx.NextIP() < ctx.LenInstr() {
+		op, param, err := ctx.Next()
+		if err != nil {
+			break
+		}
+		var dst string
+		switch op {
+		case opcode.CALL:
+			dst = strconv.Itoa(ctx.IP() + int(int8(param[0])))
+		case opcode.CALLL:
+			dst = strconv.Itoa(ctx.IP() + int(int32(binary.LittleEndian.Uint32(param))))
+		case opcode.CALLA:
+			dst = "dynamic"
+		default:
+			continue
+		}
+		if dot {
+			lines = append(lines, fmt.Sprintf("\t\"%d\" -> \"%s\";", ctx.IP(), dst))
+		} else {
+			lines = append(lines, fmt.Sprintf("%d\t%s\t%s", ctx.IP(), op, dst))
+		}
+	}
+	if dot {
+		fmt.Fprintf(c.App.Writer, "digraph calls {\n%s}\n", strings.Join(append(lines, ""), "\n"))
+		return nil
+	}
+	if len(lines) == 0 {
+		fmt.Fprintln(c.App.Writer, "no calls found")
+		return nil
+	}
+	w := tabwriter.NewWriter(c.App.Writer, 0, 0, 4, ' ', 0)
+	fmt.Fprintln(w, "INDEX\tOPCODE\tDESTINATION")
+	for _, l := range lines {
+		fmt.Fprintln(w, l)
+	}
+	return w.Flush()
+}
+
+func handleManifest(c *cli.Context) error {
+	cs := getContractStateFromContext(c.App)
+	if cs == nil {
+		return errors.New("no manifest loaded")
+	}
+	w := tabwriter.NewWriter(c.App.Writer, 0, 0, 4, ' ', 0)
+	fmt.Fprintln(w, "METHOD\tOFFSET\tPARAMETERS\tRETURN\tSAFE")
+	for _, md := range cs.Manifest.ABI.Methods {
+		params := make([]string, len(md.Parameters))
+		for i, p := range md.Parameters {
+			params[i] = p.Name + ": " + p.Type.String()
+		}
+		fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%t\n", md.Name, md.Offset, strings.Join(params, ", "), md.ReturnType, md.Safe)
+	}
+	return w.Flush()
+}
+
+func handleHash(c *cli.Context) error {
+	if !checkVMIsReady(c.App) {
+		return nil
+	}
+	var (
+		v      = getVMFromContext(c.App)
+		cs     = getContractStateFromContext(c.App)
+		script = v.Context().Program()
+	)
+	if cs != nil && len(cs.NEF.Script) != 0 {
+		script = cs.NEF.Script
+	}
+	sh := hash.Hash160(script)
+	fmt.Fprintf(c.App.Writer, "Script hash: %s\nScript address: %s\n", sh.StringLE(), address.Uint160ToString(sh))
+	if cs == nil {
+		return nil
+	}
+	var (
+		sender    util.Uint160
+		hasSender bool
+	)
+	if c.Args().Present() {
+		var err error
+		sender, err = flags.ParseAddress(c.Args().First())
+		if err != nil {
+			return fmt.Errorf("%w: failed to parse sender: %w", ErrInvalidParameter, err)
+		}
+		hasSender = true
+	} else if tx := getInteropContextFromContext(c.App).Tx; tx != nil && len(tx.Signers) != 0 {
+		sender = tx.Sender()
+		hasSender = true
+	}
+	if !hasSender {
+		fmt.Fprintln(c.App.Writer, "Provide sender to calculate deployed contract hash")
+		return nil
+	}
+	ch := state.CreateContractHash(sender, cs.NEF.Checksum, cs.Manifest.Name)
+	fmt.Fprintf(c.App.Writer, "Contract hash (sender %s): %s\n", address.Uint160ToString(sender), ch.StringLE())
+	return nil
+}
+
+func changePrompt(app *cli.App) {
+	v := getVMFromContext(app)
+	l := getReadlineInstanceFromContext(app)
+	if v.Ready() && v.Context().NextIP() >= 0 && v.Context().NextIP() < v.Context().LenInstr() {
+		l.SetPrompt(fmt.Sprintf("\033[32mNEO-GO-VM %d >\033[0m ", v.Context().NextIP()))
+	} else {
+		l.SetPrompt("\033[32mNEO-GO-VM >\033[0m ")
+	}
+}
+
+func handleVerbose(c *cli.Context) error {
+	if c.Args().Present() {
+		switch arg := c.Args().First(); arg {
+		case "on":
+			c.App.Metadata[verboseKey] = true
+		case "off":
+			c.App.Metadata[verboseKey] = false
+		default:
+			return fmt.Errorf("%w: expected 'on' or 'off', got %s", ErrInvalidParameter, arg)
+		}
+	}
+	mode := "off"
+	if getVerboseFromContext(c.App) {
+		mode = "on"
+	}
+	fmt.Fprintf(c.App.Writer, "verbose mode is %s\n", mode)
+	return nil
+}
+
+// outputSink is a named writer CLI output is copied to.
+type outputSink struct {
+	name string
+	w    io.Writer
+}
+
+// outputSinks is a set of writers CLI output (both regular and error one) is
+// copied to in addition to the terminal. It's shared by tee, record and the
+// session log, so each of them can be started and stopped independently.
+type outputSinks struct {
+	list []outputSink
+}
+
+// add registers w as a sink with the given name replacing the previous one
+// (if any).
+func (s *outputSinks) add(name string, w io.Writer) {
+	s.remove(name)
+	s.list = append(s.list, outputSink{name: name, w: w})
+}
+
+// remove unregisters the sink with the given name.
+func (s *outputSinks) remove(name string) {
+	s.list = slices.DeleteFunc(s.list, func(o outputSink) bool { return o.name == name })
+}
+
+// sinkWriter writes data to the base writer and all registered sinks.
+type sinkWriter struct {
+	base  io.Writer
+	sinks *outputSinks
+}
+
+// Write implements the io.Writer interface.
+func (w *sinkWriter) Write(p []byte) (int, error) {
+	n, err := w.base.Write(p)
+	if err != nil {
+		return n, err
+	}
+	for _, o := range w.sinks.list {
+		n, err = o.w.Write(p)
+		if err != nil {
+			return n, err
+		}
+	}
+	return len(p), nil
+}
+
+func getOutputSinksFromContext(app *cli.App) *outputSinks {
+	return app.Metadata[outputKey].(*outputSinks)
+}
+
+func handleAlias(c *cli.Context) error {
+	var (
+		args    = c.Args().Slice()
+		aliases = getAliasesFromContext(c.App)
+	)
+	if len(args) == 0 {
+		if len(aliases) == 0 {
+			fmt.Fprintln(c.App.Writer, "no aliases defined")
+			return nil
+		}
+		for _, name := range slices.Sorted(maps.Keys(aliases)) {
+			fmt.Fprintf(c.App.Writer, "%s = %s\n", name, shellquote.Join(aliases[name]...))
+		}
+		return nil
+	}
+	if len(args) < 2 {
+		return fmt.Errorf("%w: <command>", ErrMissingParameter)
+	}
+	if c.App.Command(args[0]) != nil {
+		return fmt.Errorf("%w: '%s' is a command name", ErrInvalidParameter, args[0])
+	}
+	if c.App.Command(args[1]) == nil {
+		return fmt.Errorf("%w: unknown command '%s'", ErrInvalidParameter, args[1])
+	}
+	aliases[args[0]] = slices.Clone(args[1:])
+	fmt.Fprintf(c.App.Writer, "alias '%s' defined\n", args[0])
 	return nil
 }
 
-func changePrompt(app *cli.App) {
-	v := getVMFromContext(app)
-	l := getReadlineInstanceFromContext(app)
-	if v.Ready() && v.Context().NextIP() >= 0 && v.Context().NextIP() < v.Context().LenInstr() {
-		l.SetPrompt(fmt.Sprintf("\033[32mNEO-GO-VM %d >\033[0m ", v.Context().NextIP()))
-	} else {
-		l.SetPrompt("\033[32mNEO-GO-VM >\033[0m ")
+func handleTee(c *cli.Context) error {
+	if !c.Args().Present() {
+		return fmt.Errorf("%w: <file> or 'off'", ErrMissingParameter)
+	}
+	stopTee(c.App)
+	name := c.Args().First()
+	if name == "off" {
+		fmt.Fprintln(c.App.Writer, "output mirroring is stopped")
+		return nil
+	}
+	f, err := os.Create(name)
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %w", err)
+	}
+	c.App.Metadata[teeKey] = f
+	getOutputSinksFromContext(c.App).add(teeKey, f)
+	fmt.Fprintf(c.App.Writer, "mirroring output to %s\n", name)
+	return nil
+}
+
+func handleCopy(c *cli.Context) error {
+	args := c.Args().Slice()
+	if len(args) == 0 {
+		return fmt.Errorf("%w: script, stack or ops", ErrMissingParameter)
+	}
+	var (
+		v    = getVMFromContext(c.App)
+		data string
+	)
+	switch args[0] {
+	case "script":
+		if !checkVMIsReady(c.App) {
+			return nil
+		}
+		data = hex.EncodeToString(v.Context().Program())
+	case "stack":
+		data = dumpEStack(v)
+	case "ops":
+		if !checkVMIsReady(c.App) {
+			return nil
+		}
+		out := bytes.NewBuffer(nil)
+		v.PrintOps(out)
+		data = strings.TrimSuffix(out.String(), "\n")
+	default:
+		return fmt.Errorf("%w: unknown object %s, script, stack or ops expected", ErrInvalidParameter, args[0])
+	}
+	if len(args) < 2 {
+		fmt.Fprintln(c.App.Writer, data)
+		return nil
+	}
+	if err := os.WriteFile(args[1], []byte(data+"\n"), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", args[0], err)
+	}
+	fmt.Fprintf(c.App.Writer, "%s is written to %s\n", args[0], args[1])
+	return nil
+}
+
+// recordState holds the session transcript file and the output of the command
+// being executed.
+type recordState struct {
+	file   *os.File
+	enc    *json.Encoder
+	output bytes.Buffer
+}
+
+// recordEntry is a single session transcript record.
+type recordEntry struct {
+	Command string `json:"command"`
+	Output  string `json:"output"`
+	Error   string `json:"error,omitempty"`
+}
+
+func handleRecord(c *cli.Context) error {
+	if !c.Args().Present() {
+		return fmt.Errorf("%w: <file> or 'off'", ErrMissingParameter)
+	}
+	stopRecord(c.App)
+	name := c.Args().First()
+	if name == "off" {
+		fmt.Fprintln(c.App.Writer, "recording is stopped")
+		return nil
+	}
+	f, err := os.Create(name)
+	if err != nil {
+		return fmt.Errorf("failed to create transcript file: %w", err)
+	}
+	rs := &recordState{
+		file: f,
+		enc:  json.NewEncoder(f),
+	}
+	c.App.Metadata[recordKey] = rs
+	getOutputSinksFromContext(c.App).add(recordKey, &rs.output)
+	fmt.Fprintf(c.App.Writer, "recording session to %s\n", name)
+	return nil
+}
+
+// maxSessionEntries is the maximum number of command lines kept in the
+// session log, older ones are dropped.
+const maxSessionEntries = 1000
+
+// sessionLog holds command lines executed in the session along with their
+// outputs and errors for the 'export' command.
+type sessionLog struct {
+	entries []recordEntry
+	output  bytes.Buffer
+}
+
+// add saves the command line along with its error and the output collected
+// since the last call.
+func (s *sessionLog) add(line string, err error) {
+	entry := recordEntry{
+		Command: line,
+		Output:  s.output.String(),
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	if len(s.entries) == maxSessionEntries {
+		s.entries = slices.Delete(s.entries, 0, 1)
+	}
+	s.entries = append(s.entries, entry)
+	s.output.Reset()
+}
+
+func handleExport(c *cli.Context) error {
+	if !c.Args().Present() {
+		return fmt.Errorf("%w: <file>", ErrMissingParameter)
+	}
+	var (
+		name    = c.Args().First()
+		session = c.App.Metadata[sessionKey].(*sessionLog)
+		v       = getVMFromContext(c.App)
+		buf     bytes.Buffer
+	)
+	buf.WriteString("# NeoGo VM CLI session\n")
+	for _, e := range session.entries {
+		fmt.Fprintf(&buf, "\n```\n> %s\n%s", e.Command, e.Output)
+		if e.Error != "" {
+			fmt.Fprintf(&buf, "Error: %s\n", e.Error)
+		}
+		buf.WriteString("```\n")
+	}
+	fmt.Fprintf(&buf, "\n## Final state\n\nState: %s\n", v.State())
+	if v.Ready() && v.Context().NextIP() < v.Context().LenInstr() {
+		ip, op := v.Context().NextInstr()
+		fmt.Fprintf(&buf, "\nInstruction pointer: %d (%s)\n", ip, op)
+	}
+	fmt.Fprintf(&buf, "\nEvaluation stack:\n\n```json\n%s\n```\n", dumpEStack(v))
+	if err := os.WriteFile(name, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write session: %w", err)
+	}
+	fmt.Fprintf(c.App.Writer, "session is exported to %s\n", name)
+	return nil
+}
+
+// stopRecord stops copying CLI output to the transcript and closes the
+// transcript file (if any).
+func stopRecord(app *cli.App) {
+	rs, ok := app.Metadata[recordKey].(*recordState)
+	if !ok {
+		return
+	}
+	getOutputSinksFromContext(app).remove(recordKey)
+	_ = rs.file.Close()
+	delete(app.Metadata, recordKey)
+}
+
+// stopTee stops mirroring CLI output and closes the file output is mirrored
+// to (if any).
+func stopTee(app *cli.App) {
+	f, ok := app.Metadata[teeKey].(*os.File)
+	if !ok {
+		return
+	}
+	getOutputSinksFromContext(app).remove(teeKey)
+	_ = f.Close()
+	delete(app.Metadata, teeKey)
+}
+
+// setSyscallHandler sets syscall handler of the current VM depending on the
+// verbose mode. In verbose mode Runtime.Log and Runtime.Notify messages are
+// printed to the CLI output right after the corresponding syscall.
+func setSyscallHandler(app *cli.App) {
+	ic := getInteropContextFromContext(app)
+	if ic.VM == nil {
+		return
+	}
+	if !getVerboseFromContext(app) {
+		ic.VM.SyscallHandler = ic.SyscallHandler
+		return
+	}
+	ic.VM.SyscallHandler = func(v *vm.VM, id uint32) error {
+		var (
+			msg    string
+			isLog  = id == runtimeLogID
+			ntfLen = len(ic.Notifications)
+		)
+		if isLog && v.Estack().Len() > 0 {
+			msg = v.Estack().Peek(0).String()
+		}
+		err := ic.SyscallHandler(v, id)
+		if err != nil {
+			return err
+		}
+		if isLog {
+			fmt.Fprintf(app.Writer, "LOG: %s: %s\n", v.GetCurrentScriptHash().StringLE(), msg)
+		}
+		for _, ntf := range ic.Notifications[min(ntfLen, len(ic.Notifications)):] {
+			data, err := stackitem.ToJSONWithTypesLimited(ntf.Item, dumpMaxDepth, dumpMaxSize)
+			if err != nil {
+				data = []byte("error: " + err.Error())
+			}
+			fmt.Fprintf(app.Writer, "NOTIFY: %s: %s %s\n", ntf.ScriptHash.StringLE(), ntf.Name, data)
+		}
+		return nil
 	}
 }
 
+func handleMemUsage(c *cli.Context) error {
+	v := getVMFromContext(c.App)
+	fmt.Fprintf(c.App.Writer, "References: %d/%d\nEvaluation stack items: %d\n",
+		v.RefCount(), vm.MaxStackSize, v.Estack().Len())
+	return nil
+}
+
 func handleEvents(c *cli.Context) error {
-	e, err := dumpEvents(c.App)
+	var filter []util.Uint160
+	if c.Args().Present() {
+		h, err := flags.ParseAddress(c.Args().First())
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidParameter, err)
+		}
+		filter = append(filter, h)
+	}
+	e, err := dumpEvents(c.App, filter...)
 	if err != nil {
 		writeErr(c.App.ErrWriter, err)
 		return nil
@@ -1331,6 +3532,50 @@ func handleEnv(c *cli.Context) error {
 	return nil
 }
 
+func handleContracts(c *cli.Context) error {
+	var (
+		chain = getChainFromContext(c.App)
+		ic    = getInteropContextFromContext(c.App)
+	)
+	contracts, err := native.GetContracts(ic.DAO, chain.NativeManagementID())
+	if err != nil {
+		return fmt.Errorf("failed to list contracts: %w", err)
+	}
+	if len(contracts) == 0 {
+		fmt.Fprintln(c.App.Writer, "no contracts deployed")
+		return nil
+	}
+	w := tabwriter.NewWriter(c.App.Writer, 0, 0, 4, ' ', 0)
+	fmt.Fprintln(w, "ID\tHASH\tNAME")
+	for _, cs := range contracts {
+		fmt.Fprintf(w, "%d\t0x%s\t%s\n", cs.ID, cs.Hash.StringLE(), cs.Manifest.Name)
+	}
+	return w.Flush()
+}
+
+func handleConfig(c *cli.Context) error {
+	var (
+		cfg = getChainFromContext(c.App).GetConfig()
+		hfs []string
+	)
+	for _, hf := range config.Hardforks {
+		if h, ok := cfg.Hardforks[hf.String()]; ok {
+			hfs = append(hfs, fmt.Sprintf("%s@%d", hf, h))
+		}
+	}
+	fmt.Fprintf(c.App.Writer, "Network magic: %d\n", cfg.Magic)
+	fmt.Fprintf(c.App.Writer, "StateRootInHeader: %t\n", cfg.StateRootInHeader)
+	fmt.Fprintf(c.App.Writer, "P2PSigExtensions: %t\n", cfg.P2PSigExtensions)
+	fmt.Fprintf(c.App.Writer, "P2PStateExchangeExtensions: %t\n", cfg.P2PStateExchangeExtensions)
+	fmt.Fprintf(c.App.Writer, "MaxTraceableBlocks: %d\n", cfg.MaxTraceableBlocks)
+	fmt.Fprintf(c.App.Writer, "MaxValidUntilBlockIncrement: %d\n", cfg.MaxValidUntilBlockIncrement)
+	fmt.Fprintf(c.App.Writer, "ValidatorsCount: %d\n", cfg.ValidatorsCount)
+	fmt.Fprintf(c.App.Writer, "Hardforks: %s\n", strings.Join(hfs, ", "))
+	fmt.Fprintf(c.App.Writer, "KeepOnlyLatestState: %t\n", cfg.Ledger.KeepOnlyLatestState)
+	fmt.Fprintf(c.App.Writer, "RemoveUntraceableBlocks: %t\n", cfg.Ledger.RemoveUntraceableBlocks)
+	return nil
+}
+
 func handleStorage(c *cli.Context) error {
 	id, prefix, err := getDumpArgs(c)
 	if err != nil {
@@ -1436,12 +3681,17 @@ func getContractID(c *cli.Context) (int32, error) {
 	return cs.ID, nil
 }
 
-func dumpEvents(app *cli.App) (string, error) {
-	ic := getInteropContextFromContext(app)
-	if len(ic.Notifications) == 0 {
+func dumpEvents(app *cli.App, contract ...util.Uint160) (string, error) {
+	events := getInteropContextFromContext(app).Notifications
+	if len(contract) != 0 {
+		events = slices.DeleteFunc(slices.Clone(events), func(e state.NotificationEvent) bool {
+			return e.ScriptHash != contract[0]
+		})
+	}
+	if len(events) == 0 {
 		return "", nil
 	}
-	b, err := json.MarshalIndent(ic.Notifications, "", "\t")
+	b, err := json.MarshalIndent(events, "", "\t")
 	if err != nil {
 		return "", fmt.Errorf("failed to marshal notifications: %w", err)
 	}
@@ -1463,17 +3713,126 @@ func (c *CLI) Run() error {
 			return fmt.Errorf("failed to read input: %w", err) // Critical error, stop execution.
 		}
 
-		args, err := shellquote.Split(line)
+		err = c.Eval(line)
 		if err != nil {
-			writeErr(c.shell.ErrWriter, fmt.Errorf("failed to parse arguments: %w", err))
-			continue // Not a critical error, continue execution.
+			writeErr(c.shell.ErrWriter, err) // Various command/flags parsing errors and execution errors.
 		}
+	}
+}
 
-		err = c.shell.Run(append([]string{"vm"}, args...))
+// Eval executes a single command line the same way Run does for every line
+// read from the input. The line is saved to the session log along with its
+// output and error, it's also recorded if session recording is enabled.
+func (c *CLI) Eval(line string) error {
+	if strings.TrimSpace(line) == "" {
+		return c.eval(line)
+	}
+	session := c.shell.Metadata[sessionKey].(*sessionLog)
+	session.output.Reset()
+	rs, ok := c.shell.Metadata[recordKey].(*recordState)
+	if ok {
+		rs.output.Reset()
+	}
+	err := c.eval(line)
+	session.add(line, err)
+	if !ok {
+		return err
+	}
+	// Recording could've been stopped by the command itself.
+	if c.shell.Metadata[recordKey] == rs {
+		entry := recordEntry{
+			Command: line,
+			Output:  rs.output.String(),
+		}
 		if err != nil {
-			writeErr(c.shell.ErrWriter, err) // Various command/flags parsing errors and execution errors.
+			entry.Error = err.Error()
+		}
+		// Every record is a single write to the file, so a partial
+		// transcript is still usable.
+		_ = rs.enc.Encode(entry)
+	}
+	return err
+}
+
+// eval parses the command line and executes it.
+func (c *CLI) eval(line string) error {
+	args, err := shellquote.Split(line)
+	if err != nil {
+		return fmt.Errorf("failed to parse arguments: %w", err)
+	}
+	return c.exec(args)
+}
+
+// exec executes the command with the given arguments handling user-defined
+// aliases, `repeat` and `source` commands.
+func (c *CLI) exec(args []string) error {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		return c.shell.Run(append([]string{"vm"}, args...))
+	}
+	if c.shell.Command(args[0]) == nil {
+		a, ok := getAliasesFromContext(c.shell)[args[0]]
+		if !ok {
+			return fmt.Errorf("%w: %s", ErrUnknownCommand, args[0])
+		}
+		args = append(slices.Clone(a), args[1:]...)
+	}
+	switch args[0] {
+	case "repeat":
+		return c.repeat(args[1:])
+	case "source":
+		return c.source(args[1:])
+	default:
+		return c.shell.Run(append([]string{"vm"}, args...))
+	}
+}
+
+// repeat handles `repeat` command with the given arguments.
+func (c *CLI) repeat(args []string) error {
+	if len(args) < 2 {
+		return fmt.Errorf("%w: <n> <command>", ErrMissingParameter)
+	}
+	n, err := strconv.Atoi(args[0])
+	if err != nil || n <= 0 {
+		return fmt.Errorf("%w: <n> should be a positive number", ErrInvalidParameter)
+	}
+	for i := range n {
+		err = c.exec(args[1:])
+		if err != nil {
+			return fmt.Errorf("repeat stopped after %d iteration(s): %w", i, err)
 		}
 	}
+	return nil
+}
+
+// source handles `source` command with the given arguments.
+func (c *CLI) source(args []string) error {
+	keepGoing := len(args) > 0 && args[0] == "--"+keepGoingFlagFullName
+	if keepGoing {
+		args = args[1:]
+	}
+	if len(args) != 1 {
+		return fmt.Errorf("%w: <file>", ErrMissingParameter)
+	}
+	data, err := os.ReadFile(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to read file: %w", err)
+	}
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		err = c.Eval(line)
+		if err == nil {
+			continue
+		}
+		err = fmt.Errorf("%s:%d: %w", args[0], i+1, err)
+		if !keepGoing {
+			return err
+		}
+		writeErr(c.shell.ErrWriter, err)
+	}
+	return nil
 }
 
 func handleParse(c *cli.Context) error {
@@ -1512,9 +3871,7 @@ func Parse(args []string) (string, error) {
 		}
 		buf = fmt.Appendf(buf, "Hex to String\t%s\n", fmt.Sprintf("%q", string(rawStr)))
 		buf = fmt.Appendf(buf, "Hex to Integer\t%s\n", bigint.FromBytes(rawStr))
-		var clonedStr = slices.Clone(rawStr)
-		slices.Reverse(clonedStr)
-		buf = fmt.Appendf(buf, "Swap Endianness\t%s\n", hex.EncodeToString(clonedStr))
+		buf = fmt.Appendf(buf, "Swap Endianness\t%s\n", hex.EncodeToString(reverseBytes(rawStr)))
 	}
 	if addr, err := address.StringToUint160(arg); err == nil {
 		buf = fmt.Appendf(buf, "Address to BE ScriptHash\t%s\n", addr)
@@ -1525,6 +3882,8 @@ func Parse(args []string) (string, error) {
 	if rawStr, err := base64.StdEncoding.DecodeString(arg); err == nil {
 		buf = fmt.Appendf(buf, "Base64 to String\t%s\n", fmt.Sprintf("%q", string(rawStr)))
 		buf = fmt.Appendf(buf, "Base64 to BigInteger\t%s\n", bigint.FromBytes(rawStr))
+		buf = fmt.Appendf(buf, "Base64 to Hex\t%s\n", hex.EncodeToString(rawStr))
+		buf = fmt.Appendf(buf, "Base64 Swap Endianness\t%s\n", hex.EncodeToString(reverseBytes(rawStr)))
 		if u, err := util.Uint160DecodeBytesBE(rawStr); err == nil {
 			buf = fmt.Appendf(buf, "Base64 to BE ScriptHash\t%s\n", u.StringBE())
 			buf = fmt.Appendf(buf, "Base64 to LE ScriptHash\t%s\n", u.StringLE())
@@ -1539,6 +3898,21 @@ func Parse(args []string) (string, error) {
 			buf = fmt.Appendf(buf, "Public key to Address\t%s\n", address.Uint160ToString(sh))
 		}
 	}
+	if rawStr, err := base58.CheckDecode(arg); err == nil {
+		buf = fmt.Appendf(buf, "Base58Check to Hex\t%s\n", hex.EncodeToString(rawStr))
+		if len(rawStr) == 1+util.Uint160Size && rawStr[0] == address.Prefix {
+			u, _ := util.Uint160DecodeBytesBE(rawStr[1:])
+			buf = fmt.Appendf(buf, "Base58Check to BE ScriptHash\t%s\n", u)
+			buf = fmt.Appendf(buf, "Base58Check to LE ScriptHash\t%s\n", u.Reverse())
+			buf = fmt.Appendf(buf, "Base58Check to Address\t%s\n", address.Uint160ToString(u))
+		}
+	} else if _, err := hex.DecodeString(noX); err != nil {
+		// Hex strings are mostly valid base58 ones, so don't clutter the output.
+		if rawStr, err := base58.Decode(arg); err == nil {
+			buf = fmt.Appendf(buf, "Base58 to Hex\t%s\n", hex.EncodeToString(rawStr))
+			buf = fmt.Appendf(buf, "Base58 to String\t%s\n", fmt.Sprintf("%q", string(rawStr)))
+		}
+	}
 
 	buf = fmt.Appendf(buf, "String to Hex\t%s\n", hex.EncodeToString([]byte(arg)))
 	buf = fmt.Appendf(buf, "String to Base64\t%s\n", base64.StdEncoding.EncodeToString([]byte(arg)))
@@ -1554,6 +3928,223 @@ func Parse(args []string) (string, error) {
 	return res.String(), nil
 }
 
+// stackItemDecoder is a structure that can be restored from a stack item.
+type stackItemDecoder interface {
+	FromStackItem(stackitem.Item) error
+}
+
+// decodableTypes contains constructors of structures that can be converted
+// from stack items by the 'decode' command.
+var decodableTypes = map[string]func() stackItemDecoder{
+	"abi":            func() stackItemDecoder { return new(manifest.ABI) },
+	"contract":       func() stackItemDecoder { return new(state.Contract) },
+	"deposit":        func() stackItemDecoder { return new(state.Deposit) },
+	"event":          func() stackItemDecoder { return new(manifest.Event) },
+	"group":          func() stackItemDecoder { return new(manifest.Group) },
+	"manifest":       func() stackItemDecoder { return new(manifest.Manifest) },
+	"method":         func() stackItemDecoder { return new(manifest.Method) },
+	"neobalance":     func() stackItemDecoder { return new(state.NEOBalance) },
+	"nep17balance":   func() stackItemDecoder { return new(state.NEP17Balance) },
+	"oraclerequest":  func() stackItemDecoder { return new(state.OracleRequest) },
+	"parameter":      func() stackItemDecoder { return new(manifest.Parameter) },
+	"permission":     func() stackItemDecoder { return new(manifest.Permission) },
+	"permissiondesc": func() stackItemDecoder { return new(manifest.PermissionDesc) },
+	"publickey":      func() stackItemDecoder { return new(keys.PublicKey) },
+}
+
+func handleDecode(c *cli.Context) error {
+	if !c.Args().Present() {
+		return fmt.Errorf("%w: <type>", ErrMissingParameter)
+	}
+	newF, ok := decodableTypes[strings.ToLower(c.Args().First())]
+	if !ok {
+		return fmt.Errorf("%w: unknown type %s", ErrInvalidParameter, c.Args().First())
+	}
+	v := getVMFromContext(c.App)
+	if v.Estack().Len() == 0 {
+		return errors.New("evaluation stack is empty")
+	}
+	res := newF()
+	err := res.FromStackItem(v.Estack().Peek(0).Item())
+	if err != nil {
+		return fmt.Errorf("item doesn't match %s: %w", c.Args().First(), err)
+	}
+	data, err := json.MarshalIndent(res, "", "    ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", c.Args().First(), err)
+	}
+	fmt.Fprintln(c.App.Writer, string(data))
+	return nil
+}
+
+// reverseBytes returns a reversed copy of b.
+func reverseBytes(b []byte) []byte {
+	res := slices.Clone(b)
+	slices.Reverse(res)
+	return res
+}
+
+func handleReverse(c *cli.Context) error {
+	if !c.Args().Present() {
+		return ErrMissingParameter
+	}
+	b, err := hex.DecodeString(strings.TrimPrefix(c.Args().First(), "0x"))
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidParameter, err)
+	}
+	fmt.Fprintln(c.App.Writer, hex.EncodeToString(reverseBytes(b)))
+	return nil
+}
+
+func handleEval(c *cli.Context) error {
+	if !checkVMIsReady(c.App) {
+		return nil
+	}
+	if !c.Args().Present() {
+		return fmt.Errorf("%w: <expr>", ErrMissingParameter)
+	}
+	res, err := evalExpression(getVMFromContext(c.App), strings.Join(c.Args().Slice(), " "))
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidParameter, err)
+	}
+	fmt.Fprintln(c.App.Writer, res)
+	return nil
+}
+
+// evalExpression evaluates an integer expression (see eval command
+// description for syntax) over the state of v.
+func evalExpression(v *vm.VM, expr string) (*big.Int, error) {
+	tokens := tokenizeExpression(expr)
+	if len(tokens) == 0 {
+		return nil, errors.New("empty expression")
+	}
+	var pos int
+	// term evaluates a chain of operands joined with operators from ops
+	// using next to get every operand.
+	term := func(ops string, next func() (*big.Int, error)) (*big.Int, error) {
+		res, err := next()
+		if err != nil {
+			return nil, err
+		}
+		for pos < len(tokens) && len(tokens[pos]) == 1 && strings.Contains(ops, tokens[pos]) {
+			op := tokens[pos]
+			pos++
+			arg, err := next()
+			if err != nil {
+				return nil, err
+			}
+			switch op {
+			case "+":
+				res.Add(res, arg)
+			case "-":
+				res.Sub(res, arg)
+			case "*":
+				res.Mul(res, arg)
+			default:
+				if arg.Sign() == 0 {
+					return nil, errors.New("division by zero")
+				}
+				if op == "/" {
+					res.Quo(res, arg)
+				} else {
+					res.Rem(res, arg)
+				}
+			}
+		}
+		return res, nil
+	}
+	operand := func() (*big.Int, error) {
+		if pos == len(tokens) {
+			return nil, errors.New("unexpected end of expression")
+		}
+		tok := tokens[pos]
+		pos++
+		return evalOperand(v, tok)
+	}
+	res, err := term("+-", func() (*big.Int, error) { return term("*/%", operand) })
+	if err != nil {
+		return nil, err
+	}
+	if pos != len(tokens) {
+		return nil, fmt.Errorf("unexpected %q", tokens[pos])
+	}
+	return res, nil
+}
+
+// tokenizeExpression splits expr into operators and operands.
+func tokenizeExpression(expr string) []string {
+	var (
+		tokens []string
+		start  = -1
+	)
+	for i, r := range expr {
+		isOp := strings.ContainsRune("+-*/%", r)
+		if isOp || r == ' ' || r == '\t' {
+			if start >= 0 {
+				tokens = append(tokens, expr[start:i])
+				start = -1
+			}
+			if isOp {
+				tokens = append(tokens, string(r))
+			}
+		} else if start < 0 {
+			start = i
+		}
+	}
+	if start >= 0 {
+		tokens = append(tokens, expr[start:])
+	}
+	return tokens
+}
+
+// evalOperand returns an integer value of the expression operand.
+func evalOperand(v *vm.VM, tok string) (*big.Int, error) {
+	if n, ok := new(big.Int).SetString(tok, 10); ok {
+		return n, nil
+	}
+	if tok == "estack.len" {
+		return big.NewInt(int64(v.Estack().Len())), nil
+	}
+	name, rest, ok := strings.Cut(tok, "[")
+	idxStr, ok2 := strings.CutSuffix(rest, "]")
+	if !ok || !ok2 {
+		return nil, fmt.Errorf("unsupported operand %q", tok)
+	}
+	idx, err := strconv.Atoi(idxStr)
+	if err != nil || idx < 0 {
+		return nil, fmt.Errorf("invalid index in %q", tok)
+	}
+	var item stackitem.Item
+	switch name {
+	case "estack":
+		if idx >= v.Estack().Len() {
+			return nil, fmt.Errorf("%s: evaluation stack has %d item(s)", tok, v.Estack().Len())
+		}
+		item = v.Estack().Peek(idx).Item()
+	case "sslot", "lslot", "aslot":
+		var s *vm.Slot
+		switch name {
+		case "sslot":
+			s = v.Context().StaticsSlot()
+		case "lslot":
+			s = v.Context().LocalsSlot()
+		default:
+			s = v.Context().ArgumentsSlot()
+		}
+		if idx >= s.Size() {
+			return nil, fmt.Errorf("%s: slot has %d item(s)", tok, s.Size())
+		}
+		item = s.Get(idx)
+	default:
+		return nil, fmt.Errorf("unsupported operand %q", tok)
+	}
+	n, err := item.TryInteger()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", tok, err)
+	}
+	return new(big.Int).Set(n), nil
+}
+
 const logo = `
     _   ____________        __________      _    ____  ___
    / | / / ____/ __ \      / ____/ __ \    | |  / /  |/  /
@@ -11,6 +11,8 @@ import (
 	"math/big"
 	"os"
 	"path/filepath"
+	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -27,16 +29,23 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/compiler"
 	"github.com/nspcc-dev/neo-go/pkg/config"
 	"github.com/nspcc-dev/neo-go/pkg/config/netmode"
+	"github.com/nspcc-dev/neo-go/pkg/core/fee"
+	"github.com/nspcc-dev/neo-go/pkg/core/interop"
 	"github.com/nspcc-dev/neo-go/pkg/core/interop/interopnames"
+	"github.com/nspcc-dev/neo-go/pkg/core/native/nativehashes"
 	"github.com/nspcc-dev/neo-go/pkg/core/state"
 	"github.com/nspcc-dev/neo-go/pkg/core/storage"
 	"github.com/nspcc-dev/neo-go/pkg/core/storage/dbconfig"
 	"github.com/nspcc-dev/neo-go/pkg/core/storage/dboper"
+	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/hash"
 	"github.com/nspcc-dev/neo-go/pkg/encoding/address"
 	"github.com/nspcc-dev/neo-go/pkg/io"
 	"github.com/nspcc-dev/neo-go/pkg/neotest"
 	"github.com/nspcc-dev/neo-go/pkg/neotest/chain"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract/callflag"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/manifest"
 	"github.com/nspcc-dev/neo-go/pkg/util"
 	"github.com/nspcc-dev/neo-go/pkg/vm"
 	"github.com/nspcc-dev/neo-go/pkg/vm/emit"
@@ -82,10 +91,14 @@ func newTestVMCLI(t *testing.T) *executor {
 }
 
 func newTestVMCLIWithLogo(t *testing.T, printLogo bool) *executor {
-	return newTestVMCLIWithLogoAndCustomConfig(t, printLogo, nil)
+	return newTestVMCLIWithLogoAndCustomConfig(t, printLogo, nil, Options{})
 }
 
-func newTestVMCLIWithLogoAndCustomConfig(t *testing.T, printLogo bool, cfg *config.Config) *executor {
+func newTestVMCLIWithOptions(t *testing.T, o Options) *executor {
+	return newTestVMCLIWithLogoAndCustomConfig(t, false, nil, o)
+}
+
+func newTestVMCLIWithLogoAndCustomConfig(t *testing.T, printLogo bool, cfg *config.Config, o Options) *executor {
 	e := &executor{
 		in:  &readCloser{Buffer: *bytes.NewBuffer(nil)},
 		out: bytes.NewBuffer(nil),
@@ -103,7 +116,7 @@ func newTestVMCLIWithLogoAndCustomConfig(t *testing.T, printLogo bool, cfg *conf
 		c = *cfg
 	}
 	var err error
-	e.cli, err = NewWithConfig(printLogo,
+	e.cli, err = NewWithOptions(printLogo,
 		func(int) { e.exit.Store(true) },
 		&readline.Config{
 			Prompt: "",
@@ -113,7 +126,7 @@ func newTestVMCLIWithLogoAndCustomConfig(t *testing.T, printLogo bool, cfg *conf
 			FuncIsTerminal: func() bool {
 				return false
 			},
-		}, c)
+		}, c, o)
 	require.NoError(t, err)
 	return e
 }
@@ -151,7 +164,7 @@ func newTestVMClIWithState(t *testing.T) *executor {
 	cfg.ProtocolConfiguration.StateRootInHeader = protoCfg.StateRootInHeader
 	cfg.ProtocolConfiguration.P2PStateExchangeExtensions = protoCfg.P2PStateExchangeExtensions
 	cfg.ProtocolConfiguration.Hardforks = protoCfg.Hardforks
-	return newTestVMCLIWithLogoAndCustomConfig(t, false, &cfg)
+	return newTestVMCLIWithLogoAndCustomConfig(t, false, &cfg, Options{})
 }
 
 func (e *executor) runProg(t *testing.T, commands ...string) {
@@ -338,6 +351,32 @@ func TestRun_WithNewVMContextAndBreakpoints(t *testing.T) {
 		e.checkNextLine(t, "at breakpoint 10 (ADD)*")
 		e.checkStack(t, 13)
 	})
+	t.Run("run while paused", func(t *testing.T) {
+		src := `package kek
+		func Main(a, b int) int {
+			var c = a + b
+			return c + 5
+		}`
+		tmpDir := t.TempDir()
+		filename := prepareLoadgoSrc(t, tmpDir, src)
+
+		e := newTestVMCLI(t)
+		e.runProgWithTimeout(t, 10*time.Second,
+			"loadgo "+filename,
+			"break 8",
+			"run main 3 5",
+			"run main 3 5",
+			"run --restart main 1 2",
+			"cont",
+		)
+
+		e.checkNextLine(t, "READY: loaded \\d* instructions")
+		e.checkNextLine(t, "breakpoint added at instruction 8")
+		e.checkNextLine(t, "at breakpoint 8 (PUSH5)*")
+		e.checkNextLine(t, "Error: execution is paused at instruction 8, use 'cont'.*'run --restart main 3 5'")
+		e.checkNextLine(t, "at breakpoint 8 (PUSH5)*")
+		e.checkStack(t, 8)
+	})
 	t.Run("contract breakpoints", func(t *testing.T) {
 		src := `package kek
 		func Main(a, b int) int {
@@ -365,6 +404,56 @@ func TestRun_WithNewVMContextAndBreakpoints(t *testing.T) {
 	})
 }
 
+func TestLoadGoCache(t *testing.T) {
+	src := `package kek
+	import "test.example/kek/helper"
+	func Main() int {
+		return helper.Value()
+	}`
+	helper := `package helper
+	func Value() int {
+		return 1
+	}`
+	tmpDir := t.TempDir()
+	filename := prepareLoadgoSrc(t, tmpDir, src)
+	helperFile := filepath.Join(tmpDir, "helper", "helper.go")
+	require.NoError(t, os.Mkdir(filepath.Dir(helperFile), os.ModePerm))
+	require.NoError(t, os.WriteFile(helperFile, []byte(helper), os.ModePerm))
+
+	e := newTestVMCLI(t)
+	e.runProgWithTimeout(t, 10*time.Second,
+		"loadgo "+filename,
+		"run main",
+		"loadgo "+filename,
+		"run main",
+	)
+	e.checkNextLine(t, "READY: loaded \\d* instructions")
+	e.checkStack(t, 1)
+	e.checkNextLine(t, "READY: loaded \\d* instructions")
+	e.checkStack(t, 1)
+	cache := e.cli.shell.Metadata[goCompileCacheKey].(*goCompileCache)
+	require.Equal(t, 1, cache.hits)
+	require.Len(t, cache.entries, 1)
+
+	// Change of an imported package leads to recompilation.
+	mainFile := filepath.Join(tmpDir, "vmtestcontract.go")
+	old := cache.entries[mainFile].nef
+	require.NoError(t, os.WriteFile(helperFile, []byte(strings.Replace(helper, "return 1", "return 2", 1)), os.ModePerm))
+	ne, _, err := compileGo(e.cli.shell, mainFile)
+	require.NoError(t, err)
+	require.Equal(t, 1, cache.hits)
+	require.Len(t, cache.entries, 1)
+	require.NotEqual(t, old.Script, ne.Script)
+
+	// The oldest entries are dropped.
+	for i := range maxGoCompileCacheEntries {
+		cache.add(strconv.Itoa(i), &goCompileResult{})
+	}
+	require.Len(t, cache.entries, maxGoCompileCacheEntries)
+	require.NotContains(t, cache.entries, mainFile)
+	require.Contains(t, cache.entries, "0")
+}
+
 // prepareLoadgoSrc prepares provided SC source file for loading into VM via `loadgo` command.
 func prepareLoadgoSrc(t *testing.T, tmpDir, src string) string {
 	filename := filepath.Join(tmpDir, "vmtestcontract.go")
@@ -405,6 +494,91 @@ func prepareLoadnefSrc(t *testing.T, tmpDir, src string) (string, string) {
 	return manifestFile, filename
 }
 
+func TestValidateNEF(t *testing.T) {
+	src := `package kek
+		func Main(a, b int) int {
+			return a + b
+		}`
+	tmpDir := t.TempDir()
+	manifestFile, nefFile := prepareLoadnefSrc(t, tmpDir, src)
+
+	rawNef, err := os.ReadFile(strings.Trim(nefFile, "'"))
+	require.NoError(t, err)
+	rawNef[len(rawNef)-1] ^= 0xFF // Break the checksum.
+	badNef := filepath.Join(tmpDir, "bad.nef")
+	require.NoError(t, os.WriteFile(badNef, rawNef, os.ModePerm))
+
+	m := manifest.NewManifest("Test")
+	m.ABI.Methods = []manifest.Method{
+		{Name: "main", Offset: 0, ReturnType: smartcontract.IntegerType},
+		{Name: "main", Offset: 0, ReturnType: smartcontract.IntegerType},
+		{Name: "far", Offset: 1000, ReturnType: smartcontract.VoidType},
+	}
+	rawManifest, err := json.Marshal(m)
+	require.NoError(t, err)
+	badManifest := filepath.Join(tmpDir, "bad.manifest.json")
+	require.NoError(t, os.WriteFile(badManifest, rawManifest, os.ModePerm))
+
+	e := newTestVMCLI(t)
+	e.runProg(t,
+		"validate-nef",
+		"validate-nef "+nefFile+" "+manifestFile+" extra",
+		"loadhex "+hex.EncodeToString([]byte{byte(opcode.PUSH1), byte(opcode.PUSH2), byte(opcode.ADD)}),
+		"validate-nef "+nefFile+" "+manifestFile,
+		"validate-nef "+nefFile,
+		"validate-nef "+badNef+" "+manifestFile,
+		"validate-nef "+nefFile+" "+badManifest,
+		"run",
+	)
+
+	e.checkError(t, ErrMissingParameter)
+	e.checkError(t, ErrInvalidParameter)
+	e.checkNextLine(t, "READY: loaded 3 instructions")
+	e.checkNextLineExact(t, "PASS: NEF file and manifest are valid\n")
+	e.checkNextLineExact(t, "PASS: NEF file and manifest are valid\n")
+	e.checkNextLineExact(t, "FAIL: 1 issue(s) found:\n")
+	e.checkNextLine(t, "^  - NEF: checksum verification failure")
+	e.checkNextLineExact(t, "FAIL: 2 issue(s) found:\n")
+	e.checkNextLine(t, "^  - manifest: ABI: duplicate method specifications")
+	e.checkNextLine(t, "^  - method far/0: offset 1000 is out of the script range")
+	e.checkStack(t, 3)
+}
+
+func TestLoadNEFManifestMismatch(t *testing.T) {
+	src := `package kek
+		func Main(a, b int) int {
+			return a + b
+		}`
+	tmpDir := t.TempDir()
+	manifestFile, nefFile := prepareLoadnefSrc(t, tmpDir, src)
+
+	rawManifest, err := os.ReadFile(strings.Trim(manifestFile, "'"))
+	require.NoError(t, err)
+	writeManifest := func(name string, offset int) string {
+		m := new(manifest.Manifest)
+		require.NoError(t, json.Unmarshal(rawManifest, m))
+		m.ABI.Methods[0].Offset = offset
+		raw, err := json.Marshal(m)
+		require.NoError(t, err)
+		name = filepath.Join(tmpDir, name)
+		require.NoError(t, os.WriteFile(name, raw, os.ModePerm))
+		return name
+	}
+	farManifest := writeManifest("far.manifest.json", 1000)
+	midManifest := writeManifest("mid.manifest.json", 1) // Main starts with INITSLOT.
+
+	e := newTestVMCLI(t)
+	e.runProg(t,
+		"loadnef "+nefFile+" "+farManifest,
+		"loadnef "+nefFile+" "+midManifest,
+		"loadnef "+nefFile+" "+manifestFile,
+	)
+
+	e.checkError(t, fmt.Errorf("%w: manifest does not match NEF: method main/2: offset 1000 is out of the script range", ErrInvalidParameter))
+	e.checkError(t, fmt.Errorf("%w: manifest does not match NEF: method main/2: offset 1 is not an instruction boundary", ErrInvalidParameter))
+	e.checkNextLine(t, "READY: loaded \\d+ instructions")
+}
+
 func TestLoad(t *testing.T) {
 	script := []byte{byte(opcode.PUSH3), byte(opcode.PUSH4), byte(opcode.ADD)}
 
@@ -800,6 +974,54 @@ func TestRunWithDifferentArguments(t *testing.T) {
 	})
 }
 
+func TestRunWithFileArguments(t *testing.T) {
+	src := `package kek
+	func Echo(arg []byte) []byte {
+		return arg
+	}`
+
+	tmpDir := t.TempDir()
+	filename := filepath.Join(tmpDir, "run_vmtestcontract.go")
+	require.NoError(t, os.WriteFile(filename, []byte(src), os.ModePerm))
+	filename = "'" + filename + "'"
+
+	data := []byte{0, 1, 2, 0xff, 'a', '\n'}
+	rawFile := filepath.Join(tmpDir, "arg.bin")
+	require.NoError(t, os.WriteFile(rawFile, data, os.ModePerm))
+	hexFile := filepath.Join(tmpDir, "arg.hex")
+	require.NoError(t, os.WriteFile(hexFile, []byte(hex.EncodeToString(data)+"\n"), os.ModePerm))
+	base64File := filepath.Join(tmpDir, "arg.b64")
+	require.NoError(t, os.WriteFile(base64File, []byte(base64.StdEncoding.EncodeToString(data)), os.ModePerm))
+
+	e := newTestVMCLI(t)
+	e.runProgWithTimeout(t, 30*time.Second,
+		"loadgo "+filename, "run echo '@"+rawFile+"'",
+		"loadgo "+filename, "run echo '@"+hexFile+":hex'",
+		"loadgo "+filename, "run echo '@"+base64File+":base64'",
+		"loadgo "+filename, "run echo '@"+rawFile+":hex'",
+		"loadgo "+filename, "run echo '@"+filepath.Join(tmpDir, "missing.bin")+"'",
+		"loadgo "+filename, "run echo string:@"+rawFile,
+	)
+
+	e.checkNextLine(t, "READY: loaded \\d.* instructions")
+	e.checkStack(t, data)
+
+	e.checkNextLine(t, "READY: loaded \\d.* instructions")
+	e.checkStack(t, data)
+
+	e.checkNextLine(t, "READY: loaded \\d.* instructions")
+	e.checkStack(t, data)
+
+	e.checkNextLine(t, "READY: loaded \\d.* instructions")
+	e.checkError(t, ErrInvalidParameter)
+
+	e.checkNextLine(t, "READY: loaded \\d.* instructions")
+	e.checkError(t, ErrInvalidParameter)
+
+	e.checkNextLine(t, "READY: loaded \\d.* instructions")
+	e.checkStack(t, []byte("@"+rawFile))
+}
+
 func TestPrintOps(t *testing.T) {
 	w := io.NewBufBinWriter()
 	emit.String(w.BinWriter, "log")
@@ -820,157 +1042,1371 @@ func TestPrintOps(t *testing.T) {
 	e.checkNextLine(t, "10.*PUSHDATA1.*010203")
 }
 
-func TestLoadAbort(t *testing.T) {
-	e := newTestVMCLI(t)
-	e.runProg(t,
-		"loadhex "+hex.EncodeToString([]byte{byte(opcode.PUSH1), byte(opcode.ABORT)}),
-		"run",
-	)
-
-	e.checkNextLine(t, "READY: loaded 2 instructions")
-	e.checkNextLine(t, "Error:.*at instruction 1.*ABORT")
-}
-
-func TestBreakpoint(t *testing.T) {
-	w := io.NewBufBinWriter()
-	emit.Opcodes(w.BinWriter, opcode.PUSH1, opcode.PUSH2, opcode.ADD, opcode.PUSH6, opcode.ADD)
+func TestPushInt(t *testing.T) {
 	e := newTestVMCLI(t)
 	e.runProg(t,
-		"break 3",
-		"cont",
-		"ip",
-		"loadhex "+hex.EncodeToString(w.Bytes()),
-		"break",
-		"break second",
-		"break 2",
-		"break 4",
-		"cont", "estack",
-		"run", "estack",
-		"cont",
-	)
+		"pushint",
+		"pushint five",
+		"pushint 5",
+		"ops",
+		"pushint 100500",
+		"ops",
+		"run")
 
-	e.checkNextLine(t, "no program loaded")
-	e.checkNextLine(t, "no program loaded")
-	e.checkNextLine(t, "no program loaded")
-	e.checkNextLine(t, "READY: loaded 5 instructions")
 	e.checkError(t, ErrMissingParameter)
 	e.checkError(t, ErrInvalidParameter)
-	e.checkNextLine(t, "breakpoint added at instruction 2")
-	e.checkNextLine(t, "breakpoint added at instruction 4")
-
-	e.checkNextLine(t, "at breakpoint 2.*ADD")
-	e.checkStack(t, 1, 2)
-
-	e.checkNextLine(t, "at breakpoint 4.*ADD")
-	e.checkStack(t, 3, 6)
-
-	e.checkStack(t, 9)
-}
-
-func TestDumpSSlot(t *testing.T) {
-	w := io.NewBufBinWriter()
-	emit.Opcodes(w.BinWriter, opcode.INITSSLOT, 2, // init static slot with size=2
-		opcode.PUSH5, opcode.STSFLD, 1, // put `int(5)` to sslot[1]; sslot[0] is nil
-		opcode.LDSFLD1) // put sslot[1] to the top of estack
-	e := newTestVMCLI(t)
-	e.runProg(t,
-		"loadhex "+hex.EncodeToString(w.Bytes()),
-		"break 5",
-		"step", "sslot",
-		"cont", "estack",
-	)
+	e.checkNextLine(t, "READY: loaded 1 instructions")
+	e.checkNextLine(t, "INDEX.*OPCODE.*PARAMETER")
+	e.checkNextLine(t, "0\\s+PUSH5")
+	e.checkNextLineExact(t, "\n")
 	e.checkNextLine(t, "READY: loaded 6 instructions")
-	e.checkNextLine(t, "breakpoint added at instruction 5")
-
-	e.checkNextLine(t, "at breakpoint 5.*LDSFLD1")
-	e.checkSlot(t, nil, 5)
-
-	e.checkStack(t, 5)
+	e.checkNextLine(t, "INDEX.*OPCODE.*PARAMETER")
+	e.checkNextLine(t, "0\\s+PUSH5")
+	e.checkNextLine(t, "1\\s+PUSHINT32\\s+100500")
+	e.checkNextLineExact(t, "\n")
+	e.checkStack(t, 5, 100500)
 }
 
-func TestDumpLSlot_DumpASlot(t *testing.T) {
-	w := io.NewBufBinWriter()
-	emit.Opcodes(w.BinWriter, opcode.PUSH4, opcode.PUSH5, opcode.PUSH6, // items for args slot
-		opcode.INITSLOT, 2, 3, // init local slot with size=2 and args slot with size 3
-		opcode.PUSH7, opcode.STLOC1, // put `int(7)` to lslot[1]; lslot[0] is nil
-		opcode.LDLOC, 1) // put lslot[1] to the top of estack
+func TestEmit(t *testing.T) {
 	e := newTestVMCLI(t)
 	e.runProg(t,
-		"loadhex "+hex.EncodeToString(w.Bytes()),
-		"break 6",
-		"break 8",
-		"cont", "aslot",
-		"cont", "lslot",
-		"cont", "estack",
-	)
-	e.checkNextLine(t, "READY: loaded 10 instructions")
-	e.checkNextLine(t, "breakpoint added at instruction 6")
-	e.checkNextLine(t, "breakpoint added at instruction 8")
-
-	e.checkNextLine(t, "at breakpoint 6.*PUSH7")
-	e.checkSlot(t, 6, 5, 4) // args slot
-
-	e.checkNextLine(t, "at breakpoint 8.*LDLOC")
-	e.checkSlot(t, nil, 7) // local slot
+		"emit",
+		"emit PUSH1 FOO",
+		"emit syscall System.Foo.Bar",
+		"emit int",
+		"emit int x",
+		"emit bytes zz",
+		"emit PUSH1 0xzz",
+		"emit PUSH1 PUSH2 add",
+		"run",
+		"emit string \"Hello world!\" syscall System.Runtime.Log",
+		"ops",
+		"emit INITSLOT 0x0100 int 300 STLOC0 LDLOC0 bytes 0x0102",
+		"run")
 
-	e.checkStack(t, 7)
+	e.checkError(t, ErrMissingParameter)
+	e.checkError(t, ErrInvalidParameter)
+	e.checkNextLine(t, "Error: can't parse argument: unknown syscall System.Foo.Bar, valid ones are: .*System\\.Runtime\\.Log")
+	e.checkError(t, ErrMissingParameter)
+	e.checkError(t, ErrInvalidParameter)
+	e.checkError(t, ErrInvalidParameter)
+	e.checkError(t, ErrInvalidParameter)
+	e.checkNextLine(t, "READY: loaded 3 instructions")
+	e.checkStack(t, 3)
+	e.checkNextLine(t, "READY: loaded 19 instructions")
+	e.checkNextLine(t, "INDEX.*OPCODE.*PARAMETER")
+	e.checkNextLine(t, "0.*PUSHDATA1.*48656c6c6f20776f726c6421")
+	e.checkNextLine(t, "14.*SYSCALL.*System\\.Runtime\\.Log")
+	e.checkNextLineExact(t, "\n")
+	e.checkNextLine(t, "READY: loaded 12 instructions")
+	e.checkStack(t, 300, []byte{1, 2})
 }
 
-func TestStep(t *testing.T) {
+func TestPrintOpsRange(t *testing.T) {
 	script := hex.EncodeToString([]byte{
-		byte(opcode.PUSH0), byte(opcode.PUSH1), byte(opcode.PUSH2), byte(opcode.PUSH3),
+		byte(opcode.PUSH1), byte(opcode.PUSH2), byte(opcode.ADD),
+		byte(opcode.PUSH3), byte(opcode.ADD), byte(opcode.RET),
 	})
 	e := newTestVMCLI(t)
 	e.runProg(t,
-		"step",
 		"loadhex "+script,
-		"step invalid",
-		"step",
-		"step 2",
-		"ip", "step", "ip")
+		"ops 2 2",
+		"ops 4 10",
+		"ops 6",
+		"ops 1 0",
+		"ops x",
+	)
 
-	e.checkNextLine(t, "no program loaded")
-	e.checkNextLine(t, "READY: loaded \\d+ instructions")
+	e.checkNextLine(t, "READY: loaded 6 instructions")
+	e.checkNextLine(t, "INDEX.*OPCODE.*PARAMETER")
+	e.checkNextLine(t, "2.*ADD")
+	e.checkNextLine(t, "3.*PUSH3")
+	e.checkNextLineExact(t, "\n")
+	e.checkNextLine(t, "INDEX.*OPCODE.*PARAMETER")
+	e.checkNextLine(t, "4.*ADD")
+	e.checkNextLine(t, "5.*RET")
+	e.checkNextLineExact(t, "\n")
+	e.checkError(t, ErrInvalidParameter)
+	e.checkError(t, ErrInvalidParameter)
 	e.checkError(t, ErrInvalidParameter)
-	e.checkNextLine(t, "at breakpoint 1.*PUSH1")
-	e.checkNextLine(t, "at breakpoint 3.*PUSH3")
-	e.checkNextLine(t, "instruction pointer at 3.*PUSH3")
-	e.checkNextLine(t, "execution has finished")
-	e.checkNextLine(t, "execution has finished")
 }
 
-func TestErrorOnStepInto(t *testing.T) {
-	script := hex.EncodeToString([]byte{byte(opcode.ADD)})
+func TestManifest(t *testing.T) {
+	src := `package kek
+		func Sum(first, second int) int {
+			return first + second
+		}`
+	tmpDir := t.TempDir()
+	manifestFile, nefFile := prepareLoadnefSrc(t, tmpDir, src)
+
 	e := newTestVMCLI(t)
 	e.runProg(t,
-		"stepover",
-		"loadhex "+script,
-		"stepover")
+		"manifest",
+		"loadhex "+hex.EncodeToString([]byte{byte(opcode.PUSH1)}),
+		"manifest",
+		"loadnef "+nefFile+" "+manifestFile,
+		"manifest")
 
-	e.checkNextLine(t, "Error:.*no program loaded")
+	e.checkNextLine(t, "Error: no manifest loaded")
 	e.checkNextLine(t, "READY: loaded 1 instructions")
-	e.checkNextLine(t, "Error:")
+	e.checkNextLine(t, "Error: no manifest loaded")
+	e.checkNextLine(t, "READY: loaded \\d+ instructions")
+	e.checkNextLine(t, "METHOD.*OFFSET.*PARAMETERS.*RETURN.*SAFE")
+	e.checkNextLine(t, "sum.*0.*first: Integer, second: Integer.*Integer.*false")
 }
 
-func TestStepIntoOverOut(t *testing.T) {
-	script := hex.EncodeToString([]byte{
-		byte(opcode.PUSH2), byte(opcode.CALL), 4, byte(opcode.NOP), byte(opcode.RET),
-		byte(opcode.PUSH3), byte(opcode.ADD), byte(opcode.RET),
-	})
+func TestVerbose(t *testing.T) {
+	w := io.NewBufBinWriter()
+	emit.String(w.BinWriter, "hello")
+	emit.Syscall(w.BinWriter, interopnames.SystemRuntimeLog)
+	emit.Opcodes(w.BinWriter, opcode.PUSH1)
+	script := hex.EncodeToString(w.Bytes())
 
 	e := newTestVMCLI(t)
 	e.runProg(t,
+		"verbose",
+		"verbose maybe",
+		"verbose on",
 		"loadhex "+script,
-		"step", "stepover", "run",
-		"loadhex "+script,
-		"step", "stepinto", "step", "estack", "run",
+		"run",
+		"verbose off",
 		"loadhex "+script,
-		"step", "stepinto", "stepout", "run")
+		"run")
 
-	e.checkNextLine(t, "READY: loaded 8 instructions")
-	e.checkNextLine(t, "at breakpoint 1.*CALL")
-	e.checkNextLine(t, "instruction pointer at.*NOP")
-	e.checkStack(t, 5)
+	e.checkNextLine(t, "verbose mode is off")
+	e.checkError(t, ErrInvalidParameter)
+	e.checkNextLine(t, "verbose mode is on")
+	e.checkNextLine(t, "READY: loaded 13 instructions")
+	e.checkNextLine(t, "LOG: [0-9a-f]{40}: hello")
+	e.checkStack(t, 1)
+	e.checkNextLine(t, "verbose mode is off")
+	e.checkNextLine(t, "READY: loaded 13 instructions")
+	e.checkStack(t, 1)
+}
+
+func TestUserConfig(t *testing.T) {
+	cfgPath := filepath.Join(t.TempDir(), userConfigFile)
+
+	// No file, no changes.
+	userCfg, err := loadUserConfig(cfgPath)
+	require.NoError(t, err)
+	require.Equal(t, userConfig{}, userCfg)
+
+	require.NoError(t, os.WriteFile(cfgPath, []byte("Verbose: true\nGasLimit: 1\n"), os.ModePerm))
+	userCfg, err = loadUserConfig(cfgPath)
+	require.NoError(t, err)
+	require.Equal(t, userConfig{Verbose: true, GasLimit: 1}, userCfg)
+
+	script := hex.EncodeToString([]byte{byte(opcode.PUSH1)})
+	e := newTestVMCLIWithOptions(t, Options{Verbose: userCfg.Verbose, GasLimit: userCfg.GasLimit})
+	e.runProg(t,
+		"verbose",
+		"loadhex "+script,
+		"run",
+		"loadhex --gas 1000 "+script,
+		"run")
+	e.checkNextLine(t, "verbose mode is on")
+	e.checkNextLine(t, "READY: loaded 1 instructions")
+	e.checkError(t, errors.New("at instruction 0 (PUSH1): gas limit is exceeded"))
+	e.checkNextLine(t, "READY: loaded 1 instructions")
+	e.checkStack(t, 1)
+
+	for _, bad := range []string{"Verbose: maybe", "GasLimit: -1"} {
+		require.NoError(t, os.WriteFile(cfgPath, []byte(bad), os.ModePerm))
+		_, err = loadUserConfig(cfgPath)
+		require.Error(t, err, bad)
+	}
+}
+
+func TestReadOnly(t *testing.T) {
+	w := io.NewBufBinWriter()
+	emit.Bytes(w.BinWriter, []byte{1})
+	emit.Bytes(w.BinWriter, []byte{2})
+	emit.Opcodes(w.BinWriter, opcode.PUSHNULL)
+	emit.Syscall(w.BinWriter, interopnames.SystemStoragePut)
+	put := hex.EncodeToString(w.Bytes())
+
+	w.Reset()
+	emit.AppCall(w.BinWriter, nativehashes.NeoToken, "symbol", callflag.All)
+	symbol := hex.EncodeToString(w.Bytes())
+
+	w.Reset()
+	emit.AppCall(w.BinWriter, nativehashes.NeoToken, "transfer", callflag.All,
+		util.Uint160{1}, util.Uint160{2}, 1, nil)
+	transfer := hex.EncodeToString(w.Bytes())
+
+	// The compiler emits CALLT for native contract calls.
+	src := `package kek
+		import (
+			"github.com/nspcc-dev/neo-go/pkg/interop/native/neo"
+			"github.com/nspcc-dev/neo-go/pkg/interop/runtime"
+		)
+		func Main() bool {
+			h := runtime.GetExecutingScriptHash()
+			return neo.Transfer(h, h, 1, nil)
+		}`
+	filename := prepareLoadgoSrc(t, t.TempDir(), src)
+
+	e := newTestVMCLIWithOptions(t, Options{ReadOnly: true})
+	e.runProg(t,
+		"loadhex "+put,
+		"run",
+		"loadhex "+symbol,
+		"run",
+		"loadhex "+transfer,
+		"run",
+		"loadgo "+filename,
+		"run main")
+
+	e.checkNextLine(t, "READY: loaded \\d+ instructions")
+	e.checkNextLine(t, "Error:.*missing call flags")
+	e.checkNextLine(t, "READY: loaded \\d+ instructions")
+	e.checkStack(t, stackitem.NewByteArray([]byte("NEO")))
+	e.checkNextLine(t, "READY: loaded \\d+ instructions")
+	e.checkNextLine(t, "Error:.*missing call flags")
+	e.checkNextLine(t, "READY: loaded \\d+ instructions")
+	e.checkNextLine(t, "Error:.*missing call flags")
+}
+
+func TestIStack(t *testing.T) {
+	script := []byte{
+		byte(opcode.CALL), 3,
+		byte(opcode.RET),
+		byte(opcode.PUSH1), // 3
+		byte(opcode.RET),
+	}
+	h := hash.Hash160(script).StringLE()
+	e := newTestVMCLI(t)
+	e.runProg(t,
+		"istack",
+		"loadhex "+hex.EncodeToString(script),
+		"istack",
+		"break 3",
+		"run",
+		"istack")
+
+	e.checkNextLine(t, "no program loaded")
+	e.checkNextLine(t, "READY: loaded 5 instructions")
+	e.checkNextLine(t, "^INDEX\\s+IP\\s+SCRIPT HASH\\n$")
+	e.checkNextLine(t, "^0\\s+0 \\(CALL\\)\\s+"+h+"\\n$")
+	e.checkNextLine(t, "breakpoint added at instruction 3")
+	e.checkNextLine(t, "at breakpoint 3 \\(PUSH1\\)")
+	e.checkNextLine(t, "^INDEX\\s+IP\\s+SCRIPT HASH\\n$")
+	e.checkNextLine(t, "^0\\s+3 \\(PUSH1\\)\\s+"+h+"\\n$")
+	e.checkNextLine(t, "^1\\s+2 \\(RET\\)\\s+"+h+"\\n$")
+}
+
+func TestItemStats(t *testing.T) {
+	w := io.NewBufBinWriter()
+	emit.Bytes(w.BinWriter, []byte("ab"))
+	emit.Opcodes(w.BinWriter, opcode.PUSH1, opcode.PUSH2, opcode.PACK)
+	script := hex.EncodeToString(w.Bytes())
+
+	e := newTestVMCLI(t)
+	e.runProg(t,
+		"loadhex "+script,
+		"itemstats",
+		"run",
+		"itemstats")
+
+	e.checkNextLine(t, "READY: loaded 7 instructions")
+	e.checkError(t, errors.New("evaluation stack is empty"))
+	e.checkStack(t, []stackitem.Item{stackitem.Make(1), stackitem.Make("ab")})
+	e.checkNextLine(t, "^Integer:\\s+1\\n$")
+	e.checkNextLine(t, "^ByteString:\\s+1\\n$")
+	e.checkNextLine(t, "^Array:\\s+1\\n$")
+	e.checkNextLine(t, "^Total:\\s+3 \\(serialization limit: 2048\\)\\n$")
+	e.checkNextLine(t, "^Max depth:\\s+2\\n$")
+}
+
+func TestHash(t *testing.T) {
+	script := []byte{byte(opcode.PUSH1)}
+	sh := hash.Hash160(script)
+	src := `package kek
+		func Main() int {
+			return 1
+		}`
+	tmpDir := t.TempDir()
+	manifestFile, nefFile := prepareLoadnefSrc(t, tmpDir, src)
+	sender := util.Uint160{1, 2, 3}
+
+	e := newTestVMCLI(t)
+	e.runProg(t,
+		"hash",
+		"loadhex "+hex.EncodeToString(script),
+		"hash",
+		"loadnef "+nefFile+" "+manifestFile,
+		"hash",
+		"hash "+sender.StringLE(),
+		"hash not-a-sender")
+
+	e.checkNextLine(t, "no program loaded")
+	e.checkNextLine(t, "READY: loaded 1 instructions")
+	e.checkNextLineExact(t, "Script hash: "+sh.StringLE()+"\n")
+	e.checkNextLineExact(t, "Script address: "+address.Uint160ToString(sh)+"\n")
+	e.checkNextLine(t, "READY: loaded \\d+ instructions")
+	e.checkNextLine(t, "Script hash: ")
+	e.checkNextLine(t, "Script address: ")
+	e.checkNextLine(t, "Provide sender")
+	e.checkNextLine(t, "Script hash: ")
+	e.checkNextLine(t, "Script address: ")
+	e.checkNextLine(t, "Contract hash \\(sender "+address.Uint160ToString(sender)+"\\): [0-9a-f]{40}")
+	e.checkNextLine(t, "Script hash: ")
+	e.checkNextLine(t, "Script address: ")
+	e.checkError(t, ErrInvalidParameter)
+}
+
+func TestTee(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "session.log")
+	e := newTestVMCLI(t)
+	e.runProg(t,
+		"tee",
+		"tee "+out,
+		"loadhex "+hex.EncodeToString([]byte{byte(opcode.PUSH1)}),
+		"break",
+		"tee off",
+		"loadhex "+hex.EncodeToString([]byte{byte(opcode.PUSH2), byte(opcode.PUSH3)}))
+
+	e.checkError(t, ErrMissingParameter)
+	e.checkNextLine(t, "mirroring output to")
+	e.checkNextLine(t, "READY: loaded 1 instructions")
+	e.checkError(t, ErrMissingParameter)
+	e.checkNextLine(t, "output mirroring is stopped")
+	e.checkNextLine(t, "READY: loaded 2 instructions")
+
+	data, err := os.ReadFile(out)
+	require.NoError(t, err)
+	require.Equal(t, "mirroring output to "+out+"\n"+
+		"READY: loaded 1 instructions\n"+
+		"Error: "+ErrMissingParameter.Error()+": <ip>\n", string(data))
+}
+
+func TestExport(t *testing.T) {
+	var (
+		out    = filepath.Join(t.TempDir(), "session.md")
+		script = hex.EncodeToString([]byte{byte(opcode.PUSH1), byte(opcode.PUSH2), byte(opcode.ADD)})
+		e      = newTestVMCLI(t)
+	)
+	e.runProg(t,
+		"export",
+		"loadhex "+script,
+		"step",
+		"break",
+		"export "+out)
+
+	e.checkError(t, ErrMissingParameter)
+	e.checkNextLine(t, "READY: loaded 3 instructions")
+	e.checkNextLine(t, "at breakpoint 1.*PUSH2")
+	e.checkError(t, ErrMissingParameter)
+	e.checkNextLine(t, "session is exported to "+regexp.QuoteMeta(out))
+
+	data, err := os.ReadFile(out)
+	require.NoError(t, err)
+	md := string(data)
+	require.True(t, strings.HasPrefix(md, "# NeoGo VM CLI session\n"))
+	require.Contains(t, md, "```\n> export\nError: "+ErrMissingParameter.Error()+": <file>\n```\n")
+	require.Contains(t, md, "```\n> loadhex "+script+"\nREADY: loaded 3 instructions\n```\n")
+	require.Contains(t, md, "```\n> step\nat breakpoint 1 (PUSH2)\n```\n")
+	require.Contains(t, md, "```\n> break\nError: "+ErrMissingParameter.Error()+": <ip>\n```\n")
+	require.NotContains(t, md, "> export "+out)
+	require.Contains(t, md, "State: BREAK\n")
+	require.Contains(t, md, "Instruction pointer: 1 (PUSH2)\n")
+	require.Contains(t, md, "Evaluation stack:\n\n```json\n[")
+}
+
+func TestRecord(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "session.jsonl")
+	e := newTestVMCLI(t)
+	e.runProg(t,
+		"record",
+		"record "+out,
+		"loadhex "+hex.EncodeToString([]byte{byte(opcode.PUSH1)}),
+		"break",
+		"unknown",
+		"record off",
+		"loadhex "+hex.EncodeToString([]byte{byte(opcode.PUSH2), byte(opcode.PUSH3)}))
+
+	e.checkError(t, ErrMissingParameter)
+	e.checkNextLine(t, "recording session to")
+	e.checkNextLine(t, "READY: loaded 1 instructions")
+	e.checkError(t, ErrMissingParameter)
+	e.checkError(t, ErrUnknownCommand)
+	e.checkNextLine(t, "recording is stopped")
+	e.checkNextLine(t, "READY: loaded 2 instructions")
+
+	f, err := os.Open(out)
+	require.NoError(t, err)
+	defer f.Close()
+	var (
+		dec     = json.NewDecoder(f)
+		entries []recordEntry
+	)
+	for dec.More() {
+		var entry recordEntry
+		require.NoError(t, dec.Decode(&entry))
+		entries = append(entries, entry)
+	}
+	require.Equal(t, []recordEntry{
+		{
+			Command: "loadhex " + hex.EncodeToString([]byte{byte(opcode.PUSH1)}),
+			Output:  "READY: loaded 1 instructions\n",
+		},
+		{
+			Command: "break",
+			Error:   ErrMissingParameter.Error() + ": <ip>",
+		},
+		{
+			Command: "unknown",
+			Error:   ErrUnknownCommand.Error() + ": unknown",
+		},
+	}, entries)
+}
+
+func TestTeeRecordInterleaved(t *testing.T) {
+	var (
+		dir    = t.TempDir()
+		teeOut = filepath.Join(dir, "session.log")
+		recOut = filepath.Join(dir, "session.jsonl")
+		push1  = "loadhex " + hex.EncodeToString([]byte{byte(opcode.PUSH1)})
+		push23 = "loadhex " + hex.EncodeToString([]byte{byte(opcode.PUSH2), byte(opcode.PUSH3)})
+		e      = newTestVMCLI(t)
+	)
+	e.runProg(t,
+		"tee "+teeOut,
+		"record "+recOut,
+		push1,
+		"tee off",
+		push23,
+		"record off",
+		push1)
+
+	e.checkNextLine(t, "mirroring output to")
+	e.checkNextLine(t, "recording session to")
+	e.checkNextLine(t, "READY: loaded 1 instructions")
+	e.checkNextLine(t, "output mirroring is stopped")
+	e.checkNextLine(t, "READY: loaded 2 instructions")
+	e.checkNextLine(t, "recording is stopped")
+	e.checkNextLine(t, "READY: loaded 1 instructions")
+
+	data, err := os.ReadFile(teeOut)
+	require.NoError(t, err)
+	require.Equal(t, "mirroring output to "+teeOut+"\n"+
+		"recording session to "+recOut+"\n"+
+		"READY: loaded 1 instructions\n", string(data))
+
+	f, err := os.Open(recOut)
+	require.NoError(t, err)
+	defer f.Close()
+	var (
+		dec     = json.NewDecoder(f)
+		entries []recordEntry
+	)
+	for dec.More() {
+		var entry recordEntry
+		require.NoError(t, dec.Decode(&entry))
+		entries = append(entries, entry)
+	}
+	require.Equal(t, []recordEntry{
+		{Command: push1, Output: "READY: loaded 1 instructions\n"},
+		{Command: "tee off", Output: "output mirroring is stopped\n"},
+		{Command: push23, Output: "READY: loaded 2 instructions\n"},
+	}, entries)
+}
+
+func TestMemUsage(t *testing.T) {
+	script := hex.EncodeToString([]byte{
+		byte(opcode.PUSH1), byte(opcode.PUSH2), byte(opcode.PUSH2), byte(opcode.PACK),
+		byte(opcode.PUSH3), byte(opcode.RET),
+	})
+	e := newTestVMCLI(t)
+	e.runProg(t,
+		"memusage",
+		"loadhex "+script,
+		"break 4",
+		"run",
+		"memusage",
+		"cont",
+		"memusage",
+	)
+
+	e.checkNextLine(t, "References: 0/2048")
+	e.checkNextLine(t, "Evaluation stack items: 0")
+	e.checkNextLine(t, "READY: loaded 6 instructions")
+	e.checkNextLine(t, "breakpoint added at instruction 4")
+	e.checkNextLine(t, "at breakpoint 4 \\(PUSH3\\)")
+	e.checkNextLine(t, "References: 3/2048")
+	e.checkNextLine(t, "Evaluation stack items: 1")
+	e.checkStack(t, []stackitem.Item{stackitem.Make(2), stackitem.Make(1)}, 3)
+	e.checkNextLine(t, "References: 4/2048")
+	e.checkNextLine(t, "Evaluation stack items: 2")
+}
+
+func TestEStackTree(t *testing.T) {
+	script := hex.EncodeToString([]byte{
+		byte(opcode.PUSH1), byte(opcode.PUSH2), byte(opcode.PUSH2), byte(opcode.PACK),
+		byte(opcode.PUSH3), byte(opcode.RET),
+	})
+	e := newTestVMCLI(t)
+	e.runProg(t,
+		"loadhex "+script,
+		"estack --tree",
+		"run",
+		"estack --tree",
+	)
+
+	e.checkNextLine(t, "READY: loaded 6 instructions")
+	e.checkNextLine(t, "evaluation stack is empty")
+	e.checkStack(t, []stackitem.Item{stackitem.Make(2), stackitem.Make(1)}, 3)
+	e.checkNextLine(t, "^0: Array \\(2\\)\\n$")
+	e.checkNextLine(t, "^    Integer 2\\n$")
+	e.checkNextLine(t, "^    Integer 1\\n$")
+	e.checkNextLine(t, "^1: Integer 3\\n$")
+}
+
+func TestFind(t *testing.T) {
+	w := io.NewBufBinWriter()
+	emit.Opcodes(w.BinWriter, opcode.PUSH1, opcode.PUSH2, opcode.ADD, opcode.PUSH3, opcode.ADD)
+	emit.String(w.BinWriter, "log")
+	emit.Syscall(w.BinWriter, interopnames.SystemRuntimeLog)
+	emit.Opcodes(w.BinWriter, opcode.RET)
+	require.NoError(t, w.Err)
+
+	e := newTestVMCLI(t)
+	e.runProg(t,
+		"find ADD",
+		"loadhex "+hex.EncodeToString(w.Bytes()),
+		"find ADD",
+		"find add",
+		"find syscall System.Runtime.Log",
+		"find syscall System.Runtime.Notify",
+		"find NOP",
+		"find UNKNOWN",
+		"find syscall Unknown.Interop",
+		"find",
+	)
+
+	e.checkNextLine(t, "Error:.*no program loaded")
+	e.checkNextLine(t, "READY: loaded 16 instructions")
+	e.checkNextLine(t, "found at: 2, 4")
+	e.checkNextLine(t, "found at: 2, 4")
+	e.checkNextLine(t, "found at: 10")
+	e.checkNextLine(t, "no instructions found")
+	e.checkNextLine(t, "no instructions found")
+	e.checkError(t, ErrInvalidParameter)
+	e.checkError(t, ErrInvalidParameter)
+	e.checkError(t, ErrMissingParameter)
+}
+
+func TestGasLeft(t *testing.T) {
+	const (
+		gasLimit   = 100500
+		gasLeftFee = (1 << 4) * interop.DefaultBaseExecFee // System.Runtime.GasLeft price.
+	)
+	w := io.NewBufBinWriter()
+	emit.Syscall(w.BinWriter, interopnames.SystemRuntimeGasLeft)
+	emit.Opcodes(w.BinWriter, opcode.NOP)
+	emit.Syscall(w.BinWriter, interopnames.SystemRuntimeGasLeft)
+	require.NoError(t, w.Err)
+
+	e := newTestVMCLI(t)
+	e.runProg(t,
+		"loadhex --gas "+strconv.Itoa(gasLimit)+" "+hex.EncodeToString(w.Bytes()),
+		"run",
+	)
+	e.checkNextLine(t, "READY: loaded 11 instructions")
+	e.checkStack(t, gasLimit-gasLeftFee, gasLimit-2*gasLeftFee-fee.Opcode(interop.DefaultBaseExecFee, opcode.NOP))
+}
+
+func TestFindXref(t *testing.T) {
+	w := io.NewBufBinWriter()
+	emit.Instruction(w.BinWriter, opcode.JMP, []byte{26})                      // 0
+	emit.Instruction(w.BinWriter, opcode.CALLL, []byte{24, 0, 0, 0})           // 2
+	emit.Instruction(w.BinWriter, opcode.PUSHA, []byte{19, 0, 0, 0})           // 7
+	emit.Instruction(w.BinWriter, opcode.TRY, []byte{14, 0})                   // 12
+	emit.Instruction(w.BinWriter, opcode.JMPIFNOTL, []byte{11, 0, 0, 0})       // 15
+	emit.Instruction(w.BinWriter, opcode.JMPL, []byte{0xfb, 0xff, 0xff, 0xff}) // 20
+	emit.Opcodes(w.BinWriter, opcode.NOP, opcode.RET)                          // 25, 26
+	require.NoError(t, w.Err)
+
+	e := newTestVMCLI(t)
+	e.runProg(t,
+		"find-xref 26",
+		"loadhex "+hex.EncodeToString(w.Bytes()),
+		"find-xref",
+		"find-xref x",
+		"find-xref 100",
+		"find-xref 26",
+		"find-xref 15",
+		"find-xref 25",
+	)
+
+	e.checkNextLine(t, "Error:.*no program loaded")
+	e.checkNextLine(t, "READY: loaded 27 instructions")
+	e.checkError(t, ErrMissingParameter)
+	e.checkError(t, ErrInvalidParameter)
+	e.checkError(t, ErrInvalidParameter)
+	e.checkNextLineExact(t, "referenced at: 0 (JMP), 2 (CALL_L), 7 (PUSHA), 12 (TRY), 15 (JMPIFNOT_L)\n")
+	e.checkNextLineExact(t, "referenced at: 20 (JMP_L)\n")
+	e.checkNextLine(t, "no references found")
+}
+
+func TestLoadAppend(t *testing.T) {
+	e := newTestVMCLI(t)
+	e.runProg(t,
+		"loadhex --append "+hex.EncodeToString([]byte{byte(opcode.PUSH1)}),
+		"loadbase64 --append "+base64.StdEncoding.EncodeToString([]byte{byte(opcode.PUSH2)}),
+		"loadhex --append zz",
+		"loadhex --append "+hex.EncodeToString([]byte{byte(opcode.ADD)}),
+		"run",
+		"loadhex "+hex.EncodeToString([]byte{byte(opcode.PUSH3)}),
+		"run",
+	)
+
+	e.checkNextLine(t, "READY: loaded 1 instructions")
+	e.checkNextLine(t, "READY: loaded 2 instructions")
+	e.checkError(t, ErrInvalidParameter)
+	e.checkNextLine(t, "READY: loaded 3 instructions")
+	e.checkStack(t, 3)
+	e.checkNextLine(t, "READY: loaded 1 instructions")
+	e.checkStack(t, 3)
+}
+
+func TestLoadSizeLimit(t *testing.T) {
+	var (
+		script  = bytes.Repeat([]byte{byte(opcode.NOP)}, transaction.MaxScriptLength+1)
+		hexStr  = hex.EncodeToString(script)
+		b64Str  = base64.StdEncoding.EncodeToString(script)
+		maxSize = fmt.Sprintf("script size %d exceeds the limit of %d bytes", len(script), transaction.MaxScriptLength)
+		e       = newTestVMCLI(t)
+	)
+	// Lines are too long to be passed via readline efficiently.
+	err := e.cli.Eval("loadhex " + hexStr)
+	require.ErrorIs(t, err, ErrInvalidParameter)
+	require.ErrorContains(t, err, maxSize)
+	err = e.cli.Eval("loadbase64 " + b64Str)
+	require.ErrorIs(t, err, ErrInvalidParameter)
+	require.ErrorContains(t, err, maxSize)
+
+	require.NoError(t, e.cli.Eval("loadhex --force "+hexStr))
+	e.checkNextLine(t, fmt.Sprintf("READY: loaded %d instructions", len(script)))
+	require.NoError(t, e.cli.Eval("loadbase64 --force "+b64Str))
+	e.checkNextLine(t, fmt.Sprintf("READY: loaded %d instructions", len(script)))
+}
+
+func TestDiff(t *testing.T) {
+	var (
+		tmp      = t.TempDir()
+		origFile = filepath.Join(tmp, "orig.hex")
+		modFile  = filepath.Join(tmp, "modified.hex")
+		extFile  = filepath.Join(tmp, "extended.b64")
+		badFile  = filepath.Join(tmp, "bad.txt")
+		orig     = []byte{byte(opcode.PUSH1), byte(opcode.PUSH2), byte(opcode.ADD), byte(opcode.RET)}
+		modified = []byte{byte(opcode.PUSH1), byte(opcode.PUSH3), byte(opcode.ADD), byte(opcode.RET)}
+		extended = []byte{byte(opcode.PUSH1), byte(opcode.PUSH2), byte(opcode.ADD), byte(opcode.NOP), byte(opcode.RET)}
+	)
+	require.NoError(t, os.WriteFile(origFile, []byte(hex.EncodeToString(orig)), 0644))
+	require.NoError(t, os.WriteFile(modFile, []byte(hex.EncodeToString(modified)+"\n"), 0644))
+	require.NoError(t, os.WriteFile(extFile, []byte(base64.StdEncoding.EncodeToString(extended)), 0644))
+	require.NoError(t, os.WriteFile(badFile, []byte("not a script"), 0644))
+	_, nefFile := prepareLoadnefSrc(t, tmp, `package kek
+		func Main() int {
+			return 1
+		}`)
+
+	e := newTestVMCLI(t)
+	e.runProg(t,
+		"diff "+origFile,
+		"diff "+origFile+" "+badFile,
+		"diff "+origFile+" "+origFile,
+		"diff "+origFile+" "+modFile,
+		"diff "+origFile+" "+extFile,
+		"diff "+extFile+" "+origFile,
+		"diff "+nefFile+" "+origFile)
+
+	e.checkError(t, ErrMissingParameter)
+	e.checkError(t, ErrInvalidParameter)
+	e.checkNextLineExact(t, "scripts are identical\n")
+	e.checkNextLineExact(t, "~ 1 PUSH2 => 1 PUSH3\n")
+	e.checkNextLineExact(t, "+ 3 NOP\n")
+	e.checkNextLineExact(t, "- 3 NOP\n")
+	e.checkNextLine(t, "^[~+-] ")
+}
+
+func TestInteropPrice(t *testing.T) {
+	e := newTestVMCLI(t)
+	e.runProg(t,
+		"interop",
+		"interop cost",
+		"interop price",
+		"interop price System.Unknown",
+		"interop price "+interopnames.SystemRuntimeLog,
+	)
+
+	e.checkError(t, ErrMissingParameter)
+	e.checkError(t, ErrInvalidParameter)
+	e.checkError(t, ErrMissingParameter)
+	e.checkError(t, ErrInvalidParameter)
+	e.checkNextLine(t, "^"+interopnames.SystemRuntimeLog+" price: [1-9][0-9]* \\(0\\.[0-9]+ GAS\\)\\n$")
+}
+
+func TestCallGraph(t *testing.T) {
+	script := hex.EncodeToString([]byte{
+		byte(opcode.PUSH1), byte(opcode.CALL), 3, byte(opcode.RET),
+		byte(opcode.PUSH2), byte(opcode.RET),
+	})
+	e := newTestVMCLI(t)
+	e.runProg(t,
+		"loadhex "+script,
+		"callgraph",
+		"callgraph --dot",
+		"loadhex "+hex.EncodeToString([]byte{byte(opcode.RET)}),
+		"callgraph",
+	)
+
+	e.checkNextLine(t, "READY: loaded 6 instructions")
+	e.checkNextLine(t, "INDEX.*OPCODE.*DESTINATION")
+	e.checkNextLine(t, "1\\s+CALL\\s+4")
+	e.checkNextLineExact(t, "digraph calls {\n")
+	e.checkNextLineExact(t, "\t\"1\" -> \"4\";\n")
+	e.checkNextLineExact(t, "}\n")
+	e.checkNextLine(t, "READY: loaded 1 instructions")
+	e.checkNextLine(t, "no calls found")
+}
+
+func TestUntil(t *testing.T) {
+	script := hex.EncodeToString([]byte{
+		byte(opcode.PUSH1), byte(opcode.PUSH2), byte(opcode.ADD),
+		byte(opcode.PUSH3), byte(opcode.ADD), byte(opcode.RET),
+	})
+	e := newTestVMCLI(t)
+	e.runProg(t,
+		"loadhex "+script,
+		"until 3",
+		"estack",
+		"until 6",
+		"until -1",
+		"break 4",
+		"until 5",
+		"until 5",
+		"loadhex "+script,
+		"until 2",
+		"cont",
+		"loadhex "+script,
+		"until 0",
+	)
+
+	e.checkNextLine(t, "READY: loaded 6 instructions")
+	e.checkNextLine(t, "instruction pointer at 3 \\(PUSH3\\)")
+	e.checkStack(t, 3)
+	e.checkError(t, ErrInvalidParameter)
+	e.checkError(t, ErrInvalidParameter)
+	e.checkNextLine(t, "breakpoint added at instruction 4")
+	e.checkNextLine(t, "instruction pointer at 4 \\(ADD\\)") // Stopped at breakpoint.
+	e.checkNextLine(t, "instruction pointer at 5 \\(RET\\)")
+	e.checkNextLine(t, "READY: loaded 6 instructions")
+	e.checkNextLine(t, "instruction pointer at 2 \\(ADD\\)")
+	e.checkStack(t, 6) // No breakpoint is left.
+	e.checkNextLine(t, "READY: loaded 6 instructions")
+	e.checkStack(t, 6) // Index is never reached again.
+}
+
+func TestCopy(t *testing.T) {
+	script := []byte{byte(opcode.PUSH1), byte(opcode.PUSH2), byte(opcode.RET)}
+	tmpDir := t.TempDir()
+	scriptFile := filepath.Join(tmpDir, "script.hex")
+	stackFile := filepath.Join(tmpDir, "stack.json")
+	opsFile := filepath.Join(tmpDir, "ops.txt")
+
+	e := newTestVMCLI(t)
+	e.runProg(t,
+		"copy script",
+		"loadhex "+hex.EncodeToString(script),
+		"copy script",
+		"copy script "+scriptFile,
+		"break 2",
+		"run",
+		"copy stack "+stackFile,
+		"copy ops "+opsFile,
+		"copy something",
+	)
+
+	e.checkNextLine(t, "Error:.*no program loaded")
+	e.checkNextLine(t, "READY: loaded 3 instructions")
+	e.checkNextLineExact(t, hex.EncodeToString(script)+"\n")
+	e.checkNextLine(t, "script is written to ")
+	e.checkNextLine(t, "breakpoint added at instruction 2")
+	e.checkNextLine(t, "at breakpoint 2 \\(RET\\)")
+	e.checkNextLine(t, "stack is written to ")
+	e.checkNextLine(t, "ops is written to ")
+	e.checkError(t, ErrInvalidParameter)
+
+	data, err := os.ReadFile(scriptFile)
+	require.NoError(t, err)
+	require.Equal(t, hex.EncodeToString(script)+"\n", string(data))
+
+	data, err = os.ReadFile(stackFile)
+	require.NoError(t, err)
+	var stack []map[string]any
+	require.NoError(t, json.Unmarshal(data, &stack))
+	require.Len(t, stack, 2)
+
+	data, err = os.ReadFile(opsFile)
+	require.NoError(t, err)
+	require.Contains(t, string(data), "PUSH2")
+	require.Contains(t, string(data), "<<")
+}
+
+func TestLoadAbort(t *testing.T) {
+	e := newTestVMCLI(t)
+	e.runProg(t,
+		"loadhex "+hex.EncodeToString([]byte{byte(opcode.PUSH1), byte(opcode.ABORT)}),
+		"run",
+	)
+
+	e.checkNextLine(t, "READY: loaded 2 instructions")
+	e.checkNextLine(t, "Error:.*at instruction 1.*ABORT")
+}
+
+func TestBreakpoint(t *testing.T) {
+	w := io.NewBufBinWriter()
+	emit.Opcodes(w.BinWriter, opcode.PUSH1, opcode.PUSH2, opcode.ADD, opcode.PUSH6, opcode.ADD)
+	e := newTestVMCLI(t)
+	e.runProg(t,
+		"break 3",
+		"cont",
+		"ip",
+		"loadhex "+hex.EncodeToString(w.Bytes()),
+		"break",
+		"break second",
+		"break 2",
+		"break 4",
+		"cont", "estack",
+		"run", "estack",
+		"cont",
+	)
+
+	e.checkNextLine(t, "no program loaded")
+	e.checkNextLine(t, "no program loaded")
+	e.checkNextLine(t, "no program loaded")
+	e.checkNextLine(t, "READY: loaded 5 instructions")
+	e.checkError(t, ErrMissingParameter)
+	e.checkError(t, ErrInvalidParameter)
+	e.checkNextLine(t, "breakpoint added at instruction 2")
+	e.checkNextLine(t, "breakpoint added at instruction 4")
+
+	e.checkNextLine(t, "at breakpoint 2.*ADD")
+	e.checkStack(t, 1, 2)
+
+	e.checkNextLine(t, "at breakpoint 4.*ADD")
+	e.checkStack(t, 3, 6)
+
+	e.checkStack(t, 9)
+}
+
+func TestContN(t *testing.T) {
+	// Increment the counter until it reaches 5.
+	script := []byte{
+		byte(opcode.PUSH0),
+		byte(opcode.INC), // 1
+		byte(opcode.DUP), // 2
+		byte(opcode.PUSH5),
+		byte(opcode.LT),
+		byte(opcode.JMPIF), 0xfc, // to 1
+		byte(opcode.RET),
+	}
+	e := newTestVMCLI(t)
+	e.runProg(t,
+		"loadhex "+hex.EncodeToString(script),
+		"break 2",
+		"cont 0",
+		"cont many",
+		"run", "estack",
+		"cont 2", "estack",
+		"cont 10",
+	)
+
+	e.checkNextLine(t, "READY: loaded 8 instructions")
+	e.checkNextLine(t, "breakpoint added at instruction 2")
+	e.checkError(t, ErrInvalidParameter)
+	e.checkError(t, ErrInvalidParameter)
+
+	e.checkNextLine(t, "at breakpoint 2.*DUP")
+	e.checkStack(t, 1)
+
+	e.checkNextLine(t, "at breakpoint 2.*DUP")
+	e.checkStack(t, 3)
+
+	e.checkStack(t, 5)
+}
+
+func TestBreakpointParameter(t *testing.T) {
+	w := io.NewBufBinWriter()
+	emit.Opcodes(w.BinWriter, opcode.PUSH1)
+	emit.String(w.BinWriter, "hi")
+	emit.Syscall(w.BinWriter, interopnames.SystemRuntimeLog)
+	e := newTestVMCLI(t)
+	e.runProg(t,
+		"loadhex "+hex.EncodeToString(w.Bytes()),
+		"break 1",
+		"break 5",
+		"run",
+		"cont",
+	)
+
+	e.checkNextLine(t, "READY: loaded 10 instructions")
+	e.checkNextLine(t, "breakpoint added at instruction 1")
+	e.checkNextLine(t, "breakpoint added at instruction 5")
+	e.checkNextLineExact(t, "at breakpoint 1 (PUSHDATA1) 6869 (\"hi\")\n")
+	e.checkNextLine(t, "^at breakpoint 5 \\(SYSCALL\\) "+interopnames.SystemRuntimeLog+" \\([0-9a-f]{8}\\)\\n$")
+}
+
+func TestBreakClear(t *testing.T) {
+	w := io.NewBufBinWriter()
+	emit.Opcodes(w.BinWriter, opcode.PUSH1, opcode.PUSH2, opcode.ADD, opcode.PUSH6, opcode.ADD)
+	e := newTestVMCLI(t)
+	e.runProg(t,
+		"loadhex "+hex.EncodeToString(w.Bytes()),
+		"break clear",
+		"break 1",
+		"break 2",
+		"break 4",
+		"break clear",
+		"ib",
+		"run",
+	)
+
+	e.checkNextLine(t, "READY: loaded 5 instructions")
+	e.checkNextLine(t, "0 breakpoint\\(s\\) cleared")
+	e.checkNextLine(t, "breakpoint added at instruction 1")
+	e.checkNextLine(t, "breakpoint added at instruction 2")
+	e.checkNextLine(t, "breakpoint added at instruction 4")
+	e.checkNextLine(t, "3 breakpoint\\(s\\) cleared")
+	e.checkStack(t, 9)
+}
+
+func TestDumpSSlot(t *testing.T) {
+	w := io.NewBufBinWriter()
+	emit.Opcodes(w.BinWriter, opcode.INITSSLOT, 2, // init static slot with size=2
+		opcode.PUSH5, opcode.STSFLD, 1, // put `int(5)` to sslot[1]; sslot[0] is nil
+		opcode.LDSFLD1) // put sslot[1] to the top of estack
+	e := newTestVMCLI(t)
+	e.runProg(t,
+		"loadhex "+hex.EncodeToString(w.Bytes()),
+		"break 5",
+		"step", "sslot",
+		"cont", "estack",
+	)
+	e.checkNextLine(t, "READY: loaded 6 instructions")
+	e.checkNextLine(t, "breakpoint added at instruction 5")
+
+	e.checkNextLine(t, "at breakpoint 5.*LDSFLD1")
+	e.checkSlot(t, nil, 5)
+
+	e.checkStack(t, 5)
+}
+
+func TestDumpLSlot_DumpASlot(t *testing.T) {
+	w := io.NewBufBinWriter()
+	emit.Opcodes(w.BinWriter, opcode.PUSH4, opcode.PUSH5, opcode.PUSH6, // items for args slot
+		opcode.INITSLOT, 2, 3, // init local slot with size=2 and args slot with size 3
+		opcode.PUSH7, opcode.STLOC1, // put `int(7)` to lslot[1]; lslot[0] is nil
+		opcode.LDLOC, 1) // put lslot[1] to the top of estack
+	e := newTestVMCLI(t)
+	e.runProg(t,
+		"loadhex "+hex.EncodeToString(w.Bytes()),
+		"break 6",
+		"break 8",
+		"cont", "aslot",
+		"cont", "lslot",
+		"cont", "estack",
+	)
+	e.checkNextLine(t, "READY: loaded 10 instructions")
+	e.checkNextLine(t, "breakpoint added at instruction 6")
+	e.checkNextLine(t, "breakpoint added at instruction 8")
+
+	e.checkNextLine(t, "at breakpoint 6.*PUSH7")
+	e.checkSlot(t, 6, 5, 4) // args slot
+
+	e.checkNextLine(t, "at breakpoint 8.*LDLOC")
+	e.checkSlot(t, nil, 7) // local slot
+
+	e.checkStack(t, 7)
+}
+
+func TestClear(t *testing.T) {
+	w := io.NewBufBinWriter()
+	emit.Opcodes(w.BinWriter, opcode.PUSH4, opcode.PUSH5, opcode.PUSH6, // items for args slot
+		opcode.INITSLOT, 2, 3, // init local slot with size=2 and args slot with size 3
+		opcode.PUSH7, opcode.STLOC1, // put `int(7)` to lslot[1]
+		opcode.PUSH1, opcode.PUSH2, // some junk on estack
+		opcode.LDLOC1) // put lslot[1] to the top of estack
+	e := newTestVMCLI(t)
+	e.runProg(t,
+		"clear estack",
+		"loadhex "+hex.EncodeToString(w.Bytes()),
+		"break 10",
+		"cont",
+		"clear",
+		"clear junk",
+		"clear estack", "estack",
+		"clear slots", "aslot", "lslot",
+		"ip",
+		"cont",
+	)
+	e.checkNextLine(t, "VM is not ready: no program loaded")
+	e.checkNextLine(t, "READY: loaded 11 instructions")
+	e.checkNextLine(t, "breakpoint added at instruction 10")
+	e.checkNextLine(t, "at breakpoint 10.*LDLOC1")
+	e.checkError(t, ErrMissingParameter)
+	e.checkError(t, ErrInvalidParameter)
+
+	e.checkNextLine(t, "2 item\\(s\\) removed from evaluation stack")
+	e.checkStack(t)
+
+	e.checkNextLine(t, "4 slot item\\(s\\) set to Null")
+	e.checkSlot(t, nil, nil, nil)
+	e.checkSlot(t, nil, nil)
+
+	e.checkNextLine(t, "instruction pointer at 10.*LDLOC1")
+	e.checkStack(t, stackitem.Null{})
+}
+
+func TestEval(t *testing.T) {
+	w := io.NewBufBinWriter()
+	emit.Opcodes(w.BinWriter, opcode.PUSH4, opcode.PUSH5, opcode.PUSH6, // items for args slot
+		opcode.INITSLOT, 2, 3, // init local slot with size=2 and args slot with size 3
+		opcode.PUSH7, opcode.STLOC1, // put `int(7)` to lslot[1]
+		opcode.PUSH10, opcode.PUSH3,
+		opcode.NOP)
+	e := newTestVMCLI(t)
+	e.runProg(t,
+		"eval 1",
+		"loadhex "+hex.EncodeToString(w.Bytes()),
+		"break 10",
+		"cont",
+		"eval",
+		"eval estack[0] + estack[1]",
+		"eval estack[1] - estack[0] * 2 + lslot[1]",
+		"eval estack.len",
+		"eval aslot[0]%4/2",
+		"eval lslot[0]",
+		"eval estack[5]",
+		"eval sslot[0]",
+		"eval foo",
+		"eval 1 / 0",
+		"eval 1 +",
+		"eval 1 2",
+	)
+	e.checkNextLine(t, "VM is not ready: no program loaded")
+	e.checkNextLine(t, "READY: loaded 11 instructions")
+	e.checkNextLine(t, "breakpoint added at instruction 10")
+	e.checkNextLine(t, "at breakpoint 10.*NOP")
+	e.checkError(t, ErrMissingParameter)
+	e.checkNextLineExact(t, "13\n")
+	e.checkNextLineExact(t, "11\n")
+	e.checkNextLineExact(t, "2\n")
+	e.checkNextLineExact(t, "1\n")
+	for range 7 {
+		e.checkError(t, ErrInvalidParameter)
+	}
+}
+
+func TestStep(t *testing.T) {
+	script := hex.EncodeToString([]byte{
+		byte(opcode.PUSH0), byte(opcode.PUSH1), byte(opcode.PUSH2), byte(opcode.PUSH3),
+	})
+	e := newTestVMCLI(t)
+	e.runProg(t,
+		"step",
+		"loadhex "+script,
+		"step invalid",
+		"step",
+		"step 2",
+		"ip", "step", "ip")
+
+	e.checkNextLine(t, "no program loaded")
+	e.checkNextLine(t, "READY: loaded \\d+ instructions")
+	e.checkError(t, ErrInvalidParameter)
+	e.checkNextLine(t, "at breakpoint 1.*PUSH1")
+	e.checkNextLine(t, "at breakpoint 3.*PUSH3")
+	e.checkNextLine(t, "instruction pointer at 3.*PUSH3")
+	e.checkNextLine(t, "execution has finished")
+	e.checkNextLine(t, "execution has finished")
+}
+
+func TestStepIntoSyscall(t *testing.T) {
+	w := io.NewBufBinWriter()
+	emit.Opcodes(w.BinWriter, opcode.PUSH1)                  // 0
+	emit.String(w.BinWriter, "hi")                           // 1
+	emit.Syscall(w.BinWriter, interopnames.SystemRuntimeLog) // 5
+	emit.Opcodes(w.BinWriter, opcode.PUSH2, opcode.PUSH3)    // 10
+	require.NoError(t, w.Err)
+
+	e := newTestVMCLI(t)
+	e.runProg(t,
+		"loadhex "+hex.EncodeToString(w.Bytes()),
+		"step --into-syscall invalid",
+		"step --into-syscall",
+		"step --into-syscall 10",
+		"step --into-syscall 2",
+		"step --into-syscall 10")
+
+	e.checkNextLine(t, "READY: loaded 12 instructions")
+	e.checkError(t, ErrInvalidParameter)
+	e.checkNextLine(t, "instruction pointer at 1 \\(PUSHDATA1\\)")
+	e.checkNextLine(t, "instruction pointer at 5 \\(SYSCALL\\)")
+	e.checkNextLine(t, "^paused before SYSCALL "+interopnames.SystemRuntimeLog+" \\([0-9a-f]{8}\\)\\n$")
+	e.checkNextLineExact(t, "Interop arguments (topmost estack items):\n")
+	e.checkNextLineExact(t, "0: ByteString 6869\n")
+	e.checkNextLineExact(t, "1: Integer 1\n")
+	e.checkNextLine(t, "instruction pointer at 11 \\(PUSH3\\)")
+	e.checkStack(t, 1, 2, 3)
+}
+
+func TestRepeat(t *testing.T) {
+	script := hex.EncodeToString([]byte{
+		byte(opcode.PUSH0), byte(opcode.PUSH1), byte(opcode.PUSH2), byte(opcode.PUSH3), byte(opcode.PUSH4),
+	})
+	e := newTestVMCLI(t)
+	e.runProg(t,
+		"repeat",
+		"repeat 2",
+		"repeat 0 step",
+		"repeat many step",
+		"loadhex "+script,
+		"repeat 3 step",
+		"repeat 2 ip",
+		"repeat 2 repeat 2 break",
+	)
+
+	e.checkError(t, ErrMissingParameter)
+	e.checkError(t, ErrMissingParameter)
+	e.checkError(t, ErrInvalidParameter)
+	e.checkError(t, ErrInvalidParameter)
+	e.checkNextLine(t, "READY: loaded \\d+ instructions")
+	e.checkNextLine(t, "at breakpoint 1.*PUSH1")
+	e.checkNextLine(t, "at breakpoint 2.*PUSH2")
+	e.checkNextLine(t, "at breakpoint 3.*PUSH3")
+	e.checkNextLine(t, "instruction pointer at 3.*PUSH3")
+	e.checkNextLine(t, "instruction pointer at 3.*PUSH3")
+	e.checkError(t, fmt.Errorf("repeat stopped after 0 iteration(s): repeat stopped after 0 iteration(s): %w", ErrMissingParameter))
+}
+
+func TestAlias(t *testing.T) {
+	script := hex.EncodeToString([]byte{
+		byte(opcode.PUSH0), byte(opcode.PUSH1), byte(opcode.PUSH2), byte(opcode.PUSH3),
+	})
+	e := newTestVMCLI(t)
+	e.runProg(t,
+		"alias",
+		"alias st",
+		"alias step ip",
+		"alias c ip",
+		"alias st unknown",
+		"alias st estack --tree",
+		"alias ii ip",
+		"alias",
+		"loadhex "+script,
+		"s 2",
+		"es",
+		"st",
+		"b 3",
+		"c",
+		"ii",
+	)
+
+	e.checkNextLine(t, "no aliases defined")
+	e.checkError(t, ErrMissingParameter)
+	e.checkError(t, ErrInvalidParameter)
+	e.checkError(t, ErrInvalidParameter)
+	e.checkError(t, ErrInvalidParameter)
+	e.checkNextLineExact(t, "alias 'st' defined\n")
+	e.checkNextLineExact(t, "alias 'ii' defined\n")
+	e.checkNextLineExact(t, "ii = ip\n")
+	e.checkNextLineExact(t, "st = estack --tree\n")
+	e.checkNextLine(t, "READY: loaded 4 instructions")
+	e.checkNextLine(t, "at breakpoint 2.*PUSH2")
+	e.checkStack(t, 0, 1)
+	e.checkNextLineExact(t, "0: Integer 0\n")
+	e.checkNextLineExact(t, "1: Integer 1\n")
+	e.checkNextLine(t, "breakpoint added at instruction 3")
+	e.checkNextLine(t, "at breakpoint 3.*PUSH3")
+	e.checkNextLine(t, "instruction pointer at 3.*PUSH3")
+}
+
+func TestSource(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "script.txt")
+	require.NoError(t, os.WriteFile(file, []byte(`# Comment.
+loadhex 1112
+
+stpe
+run
+`), 0644))
+
+	t.Run("strict", func(t *testing.T) {
+		e := newTestVMCLI(t)
+		e.runProg(t,
+			"source",
+			"source "+file+".missing",
+			"source "+file,
+			"ip")
+
+		e.checkError(t, ErrMissingParameter)
+		e.checkNextLine(t, "Error: failed to read file")
+		e.checkNextLine(t, "READY: loaded 2 instructions")
+		e.checkError(t, fmt.Errorf("%s:4: %w: stpe", file, ErrUnknownCommand))
+		// The script is not executed.
+		e.checkNextLine(t, "instruction pointer at 0 \\(PUSH1\\)")
+	})
+	t.Run("keep going", func(t *testing.T) {
+		e := newTestVMCLI(t)
+		e.runProg(t, "source --keep-going "+file)
+
+		e.checkNextLine(t, "READY: loaded 2 instructions")
+		e.checkError(t, fmt.Errorf("%s:4: %w: stpe", file, ErrUnknownCommand))
+		e.checkStack(t, 1, 2)
+	})
+	t.Run("interactive", func(t *testing.T) {
+		e := newTestVMCLI(t)
+		e.runProg(t,
+			"loadhex 1112",
+			"stpe",
+			"run")
+
+		e.checkNextLine(t, "READY: loaded 2 instructions")
+		e.checkError(t, fmt.Errorf("%w: stpe", ErrUnknownCommand))
+		e.checkStack(t, 1, 2)
+	})
+}
+
+func TestStepUntilFault(t *testing.T) {
+	good := hex.EncodeToString([]byte{byte(opcode.PUSH1), byte(opcode.PUSH2), byte(opcode.ADD)})
+	bad := hex.EncodeToString([]byte{byte(opcode.PUSH1), byte(opcode.PUSH0), byte(opcode.DIV), byte(opcode.RET)})
+	e := newTestVMCLI(t)
+	e.runProg(t,
+		"stepuntil-fault",
+		"loadhex "+good,
+		"break 1",
+		"stepuntil-fault",
+		"loadhex "+bad,
+		"stepuntil-fault")
+
+	e.checkNextLine(t, "no program loaded")
+	e.checkNextLine(t, "READY: loaded 3 instructions")
+	e.checkNextLine(t, "breakpoint added at instruction 1")
+	e.checkNextLineExact(t, "HALT after 4 step(s)\n")
+	e.checkStack(t, 3)
+	e.checkNextLine(t, "READY: loaded 4 instructions")
+	e.checkNextLine(t, "Error:.*division by zero")
+	e.checkNextLineExact(t, "FAULT after 3 step(s)\n")
+	e.checkNextLineExact(t, "FAULT at instruction 2 (DIV)\n")
+}
+
+func TestDryRun(t *testing.T) {
+	var (
+		h1 = util.Uint160{1, 2, 3}
+		h2 = util.Uint160{4, 5, 6}
+		w  = io.NewBufBinWriter()
+	)
+	emit.Bytes(w.BinWriter, h1.BytesBE())
+	emit.Syscall(w.BinWriter, interopnames.SystemRuntimeCheckWitness)
+	emit.Opcodes(w.BinWriter, opcode.ASSERT)
+	emit.Bytes(w.BinWriter, h2.BytesBE())
+	emit.Syscall(w.BinWriter, interopnames.SystemRuntimeCheckWitness)
+	checkWitnesses := hex.EncodeToString(w.Bytes())
+
+	w.Reset()
+	emit.AppCall(w.BinWriter, nativehashes.GasToken, "symbol", callflag.All)
+	symbol := hex.EncodeToString(w.Bytes())
+
+	var (
+		h1Str = fmt.Sprintf("  %s (%s)", h1.StringLE(), address.Uint160ToString(h1))
+		h2Str = fmt.Sprintf("  %s (%s)", h2.StringLE(), address.Uint160ToString(h2))
+		e     = newTestVMCLI(t)
+	)
+	e.runProg(t,
+		"dryrun",
+		"loadhex "+checkWitnesses,
+		"dryrun",
+		"dryrun --assume-signed",
+		"ip",
+		"loadhex "+checkWitnesses+" "+cmdargs.CosignersSeparator+" "+address.Uint160ToString(h1),
+		"dryrun",
+		"loadhex "+symbol,
+		"dryrun")
+
+	e.checkNextLine(t, "no program loaded")
+	e.checkNextLine(t, "READY: loaded \\d+ instructions")
+	e.checkNextLineExact(t, "Required witnesses:\n")
+	e.checkNextLineExact(t, h1Str+": missing\n")
+	e.checkNextLine(t, "Dry run FAULT: .*ASSERT")
+	e.checkNextLineExact(t, "Required witnesses:\n")
+	e.checkNextLineExact(t, h1Str+": missing\n")
+	e.checkNextLineExact(t, h2Str+": missing\n")
+	e.checkNextLine(t, "instruction pointer at 0")
+	e.checkNextLine(t, "READY: loaded \\d+ instructions")
+	e.checkNextLineExact(t, "Required witnesses:\n")
+	e.checkNextLineExact(t, h1Str+": signed\n")
+	e.checkNextLineExact(t, h2Str+": missing\n")
+	e.checkNextLine(t, "READY: loaded \\d+ instructions")
+	e.checkNextLineExact(t, "no witnesses required\n")
+}
+
+func TestErrorOnStepInto(t *testing.T) {
+	script := hex.EncodeToString([]byte{byte(opcode.ADD)})
+	e := newTestVMCLI(t)
+	e.runProg(t,
+		"stepover",
+		"loadhex "+script,
+		"stepover")
+
+	e.checkNextLine(t, "Error:.*no program loaded")
+	e.checkNextLine(t, "READY: loaded 1 instructions")
+	e.checkNextLine(t, "Error:")
+	e.checkNextLine(t, "FAULT at instruction 0 \\(ADD\\)")
+}
+
+func TestFaultOnStep(t *testing.T) {
+	script := hex.EncodeToString([]byte{byte(opcode.PUSH1), byte(opcode.ADD), byte(opcode.RET)})
+	e := newTestVMCLI(t)
+	e.runProg(t,
+		"loadhex "+script,
+		"step",
+		"stepinto",
+		"estack",
+		"loadhex "+script,
+		"step 2",
+	)
+
+	e.checkNextLine(t, "READY: loaded 3 instructions")
+	e.checkNextLine(t, "at breakpoint 1 \\(ADD\\)")
+	e.checkNextLine(t, "Error:.*at instruction 1 \\(ADD\\)")
+	e.checkNextLine(t, "FAULT at instruction 1 \\(ADD\\)")
+	e.checkStack(t)
+	e.checkNextLine(t, "READY: loaded 3 instructions")
+	e.checkNextLine(t, "Error:.*at instruction 1 \\(ADD\\)")
+	e.checkNextLine(t, "FAULT at instruction 1 \\(ADD\\)")
+}
+
+func TestStepIntoOverOut(t *testing.T) {
+	script := hex.EncodeToString([]byte{
+		byte(opcode.PUSH2), byte(opcode.CALL), 4, byte(opcode.NOP), byte(opcode.RET),
+		byte(opcode.PUSH3), byte(opcode.ADD), byte(opcode.RET),
+	})
+
+	e := newTestVMCLI(t)
+	e.runProg(t,
+		"loadhex "+script,
+		"step", "stepover", "run",
+		"loadhex "+script,
+		"step", "stepinto", "step", "estack", "run",
+		"loadhex "+script,
+		"step", "stepinto", "stepout", "run")
+
+	e.checkNextLine(t, "READY: loaded 8 instructions")
+	e.checkNextLine(t, "at breakpoint 1.*CALL")
+	e.checkNextLine(t, "instruction pointer at.*NOP")
+	e.checkStack(t, 5)
 
 	e.checkNextLine(t, "READY: loaded 8 instructions")
 	e.checkNextLine(t, "at breakpoint.*CALL")
@@ -1003,6 +2439,8 @@ func TestParse(t *testing.T) {
 		e.checkNextLine(t, "Swap Endianness.*6766")
 		e.checkNextLine(t, "Base64 to String.*\"뮻\"")
 		e.checkNextLine(t, "Base64 to BigInteger.*-4477205")
+		e.checkNextLine(t, "Base64 to Hex.*ebaebb")
+		e.checkNextLine(t, "Base64 Swap Endianness.*bbaeeb")
 		e.checkNextLine(t, "String to Hex.*36363637")
 		e.checkNextLine(t, "String to Base64.*NjY2Nw==")
 	})
@@ -1013,11 +2451,29 @@ func TestParse(t *testing.T) {
 		e.checkNextLine(t, "Address to LE ScriptHash.*eb88a496178256213f674eb302e44f9d85cf8aaa")
 		e.checkNextLine(t, "Address to Base64.*(BE).*qorPhZ1P5AKzTmc/IVaCF5akiOs=")
 		e.checkNextLine(t, "Address to Base64.*(LE).*64iklheCViE/Z06zAuRPnYXPiqo=")
+		e.checkNextLine(t, "Base58Check to Hex.*35aa8acf859d4fe402b34e673f2156821796a488eb")
+		e.checkNextLine(t, "Base58Check to BE ScriptHash.*aa8acf859d4fe402b34e673f2156821796a488eb")
+		e.checkNextLine(t, "Base58Check to LE ScriptHash.*eb88a496178256213f674eb302e44f9d85cf8aaa")
+		e.checkNextLine(t, "Base58Check to Address.*NbTiM6h8r99kpRtb428XcsUk1TzKed2gTc")
 		e.checkNextLine(t, "String to Hex.*4e6254694d3668387239396b70527462343238586373556b31547a4b656432675463")
 		e.checkNextLine(t, "String to Base64.*TmJUaU02aDhyOTlrcFJ0YjQyOFhjc1VrMVR6S2VkMmdUYw==")
 	})
+	t.Run("base58", func(t *testing.T) {
+		e := newTestVMCLI(t)
+		e.runProg(t, "parse StV1DL6CwTryKyV")
+		e.checkNextLine(t, "Base58 to Hex.*68656c6c6f20776f726c64")
+		e.checkNextLine(t, "Base58 to String.*\"hello world\"")
+		e.checkNextLine(t, "String to Hex\\s+")
+		e.checkNextLine(t, "String to Base64\\s+")
+	})
 	t.Run("Uint160", func(t *testing.T) {
 		u := util.Uint160{66, 67, 68}
+		// LE string is a valid base64 too.
+		b64, err := base64.StdEncoding.DecodeString(u.StringLE())
+		require.NoError(t, err)
+		b64Hex := hex.EncodeToString(b64)
+		slices.Reverse(b64)
+		b64Swapped := hex.EncodeToString(b64)
 		e := newTestVMCLI(t)
 		e.runProg(t, "parse "+u.StringLE())
 		e.checkNextLine(t, "Integer to Hex.*b6c706")
@@ -1029,6 +2485,8 @@ func TestParse(t *testing.T) {
 		e.checkNextLine(t, "Swap Endianness.*4243440000000000000000000000000000000000")
 		e.checkNextLine(t, "Base64 to String.*")
 		e.checkNextLine(t, "Base64 to BigInteger.*376115185060690908522683414825349447309891933036899526770189324554358227")
+		e.checkNextLine(t, "Base64 to Hex.*"+b64Hex)
+		e.checkNextLine(t, "Base64 Swap Endianness.*"+b64Swapped)
 		e.checkNextLine(t, "String to Hex.*30303030303030303030303030303030303030303030303030303030303030303030343434333432")
 		e.checkNextLine(t, "String to Base64.*MDAwMDAwMDAwMDAwMDAwMDAwMDAwMDAwMDAwMDAwMDAwMDQ0NDM0Mg==")
 	})
@@ -1052,6 +2510,8 @@ func TestParse(t *testing.T) {
 		e.runProg(t, "parse "+base64.StdEncoding.EncodeToString(u.BytesBE()))
 		e.checkNextLine(t, "Base64 to String\\s+")
 		e.checkNextLine(t, "Base64 to BigInteger\\s+")
+		e.checkNextLine(t, "Base64 to Hex\\s+"+u.StringBE())
+		e.checkNextLine(t, "Base64 Swap Endianness\\s+"+u.StringLE())
 		e.checkNextLine(t, "Base64 to BE ScriptHash\\s+"+u.StringBE())
 		e.checkNextLine(t, "Base64 to LE ScriptHash\\s+"+u.StringLE())
 		e.checkNextLine(t, "Base64 to Address \\(BE\\)\\s+"+address.Uint160ToString(u))
@@ -1061,6 +2521,56 @@ func TestParse(t *testing.T) {
 	})
 }
 
+func TestDecode(t *testing.T) {
+	w := io.NewBufBinWriter()
+	emit.Int(w.BinWriter, int64(smartcontract.IntegerType))
+	emit.String(w.BinWriter, "amount")
+	emit.Opcodes(w.BinWriter, opcode.PUSH2, opcode.PACKSTRUCT)
+	require.NoError(t, w.Err)
+
+	e := newTestVMCLI(t)
+	e.runProg(t,
+		"decode parameter",
+		"loadhex "+hex.EncodeToString(w.Bytes()),
+		"run",
+		"decode",
+		"decode unknown",
+		"decode publickey",
+		"decode Parameter",
+	)
+
+	e.checkNextLine(t, "Error: evaluation stack is empty")
+	e.checkNextLine(t, "READY: loaded \\d+ instructions")
+	e.checkStack(t, stackitem.NewStruct([]stackitem.Item{
+		stackitem.Make("amount"),
+		stackitem.Make(int(smartcontract.IntegerType)),
+	}))
+	e.checkError(t, ErrMissingParameter)
+	e.checkError(t, ErrInvalidParameter)
+	e.checkNextLine(t, "Error: item doesn't match publickey")
+	e.checkNextLineExact(t, "{\n")
+	e.checkNextLineExact(t, "    \"name\": \"amount\",\n")
+	e.checkNextLineExact(t, "    \"type\": \"Integer\"\n")
+	e.checkNextLineExact(t, "}\n")
+}
+
+func TestReverse(t *testing.T) {
+	u := util.Uint160{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20}
+	e := newTestVMCLI(t)
+	e.runProg(t,
+		"reverse",
+		"reverse qwerty",
+		"reverse "+u.StringLE(),
+		"reverse 0x"+u.StringBE(),
+		"reverse 0102ff")
+
+	e.checkError(t, ErrMissingParameter)
+	e.checkError(t, ErrInvalidParameter)
+	e.checkNextLineExact(t, u.StringBE()+"\n")
+	e.checkNextLineExact(t, u.StringLE()+"\n")
+	e.checkNextLineExact(t, "ff0201\n")
+}
+
 func TestPrintLogo(t *testing.T) {
 	e := newTestVMCLIWithLogo(t, true)
 	e.runProg(t)
@@ -1074,6 +2584,47 @@ func TestExit(t *testing.T) {
 	require.True(t, e.exit.Load())
 }
 
+func TestScriptSlots(t *testing.T) {
+	other := filepath.Join(t.TempDir(), "other.hex")
+	require.NoError(t, os.WriteFile(other, []byte(hex.EncodeToString([]byte{byte(opcode.PUSH5)})), 0644))
+	e := newTestVMCLI(t)
+	e.runProg(t,
+		"loadslot other",
+		"switch other",
+		"loadhex "+hex.EncodeToString([]byte{byte(opcode.PUSH1), byte(opcode.PUSH2)}),
+		"loadslot other "+other,
+		"ops",
+		"slots",
+		"switch main",
+		"ops",
+		"run",
+		"switch main",
+		"switch other",
+		"run",
+		"slots")
+
+	e.checkError(t, ErrMissingParameter)
+	e.checkError(t, ErrInvalidParameter)
+	e.checkNextLine(t, "READY: loaded 2 instructions")
+	e.checkNextLine(t, "READY: loaded 1 instructions into slot 'other'")
+	e.checkNextLine(t, "INDEX.*OPCODE.*PARAMETER")
+	e.checkNextLine(t, "0\\s+PUSH5")
+	e.checkNextLineExact(t, "\n")
+	e.checkNextLine(t, "^  main\\s+2 instructions\\n$")
+	e.checkNextLine(t, "^\\* other\\s+1 instructions\\n$")
+	e.checkNextLine(t, "switched to slot 'main'")
+	e.checkNextLine(t, "INDEX.*OPCODE.*PARAMETER")
+	e.checkNextLine(t, "0\\s+PUSH1")
+	e.checkNextLine(t, "1\\s+PUSH2")
+	e.checkNextLineExact(t, "\n")
+	e.checkStack(t, 1, 2)
+	e.checkNextLine(t, "slot 'main' is already active")
+	e.checkNextLine(t, "switched to slot 'other'")
+	e.checkStack(t, 5)
+	e.checkNextLine(t, "^  main\\s+2 instructions\\n$")
+	e.checkNextLine(t, "^\\* other\\s+1 instructions\\n$")
+}
+
 func TestReset(t *testing.T) {
 	script := []byte{byte(opcode.PUSH1)}
 	e := newTestVMCLI(t)
@@ -1160,6 +2711,50 @@ func TestEvents(t *testing.T) {
 	e.checkEvents(t, false, expectedEvent) // printed after `events` command
 }
 
+func TestEventsFilter(t *testing.T) {
+	to := util.Uint160{1, 2, 3}
+	w := io.NewBufBinWriter()
+	// Zero transfers from the entry script itself don't need any witness.
+	for _, h := range []util.Uint160{nativehashes.GasToken, nativehashes.NeoToken} {
+		emit.Opcodes(w.BinWriter, opcode.PUSHNULL)
+		emit.Int(w.BinWriter, 0)
+		emit.Bytes(w.BinWriter, to.BytesBE())
+		emit.Syscall(w.BinWriter, interopnames.SystemRuntimeGetExecutingScriptHash)
+		emit.Int(w.BinWriter, 4)
+		emit.Opcodes(w.BinWriter, opcode.PACK)
+		emit.AppCallNoArgs(w.BinWriter, h, "transfer", callflag.All)
+	}
+	script := w.Bytes()
+	transfer := func(h util.Uint160) state.NotificationEvent {
+		return state.NotificationEvent{
+			ScriptHash: h,
+			Name:       "Transfer",
+			Item: stackitem.NewArray([]stackitem.Item{
+				stackitem.NewByteArray(hash.Hash160(script).BytesBE()),
+				stackitem.NewByteArray(to.BytesBE()),
+				stackitem.Make(0),
+			}),
+		}
+	}
+
+	e := newTestVMCLI(t)
+	e.runProg(t,
+		"loadhex "+hex.EncodeToString(script),
+		"run",
+		"events "+nativehashes.GasToken.StringLE(),
+		"notifications "+address.Uint160ToString(nativehashes.NeoToken),
+		"events "+to.StringLE(),
+		"events bad")
+
+	e.checkNextLine(t, "READY: loaded \\d+ instructions")
+	e.checkStack(t, true, true)
+	e.checkEvents(t, true, transfer(nativehashes.GasToken), transfer(nativehashes.NeoToken))
+	e.checkEvents(t, false, transfer(nativehashes.GasToken))
+	e.checkEvents(t, false, transfer(nativehashes.NeoToken))
+	e.checkNextLineExact(t, "\n")
+	e.checkError(t, ErrInvalidParameter)
+}
+
 func TestEnv(t *testing.T) {
 	t.Run("default setup", func(t *testing.T) {
 		e := newTestVMCLI(t)
@@ -1198,6 +2793,31 @@ func TestEnv(t *testing.T) {
 	})
 }
 
+func TestConfig(t *testing.T) {
+	check := func(t *testing.T, e *executor, stateRootInHeader bool) {
+		e.checkNextLine(t, "Network magic: 42")
+		e.checkNextLine(t, fmt.Sprintf("StateRootInHeader: %t", stateRootInHeader))
+		e.checkNextLine(t, "P2PSigExtensions: ")
+		e.checkNextLine(t, "P2PStateExchangeExtensions: ")
+		e.checkNextLine(t, "MaxTraceableBlocks: \\d+")
+		e.checkNextLine(t, "MaxValidUntilBlockIncrement: \\d+")
+		e.checkNextLine(t, "ValidatorsCount: \\d+")
+		e.checkNextLine(t, "Hardforks: ")
+		e.checkNextLine(t, "KeepOnlyLatestState: ")
+		e.checkNextLine(t, "RemoveUntraceableBlocks: ")
+	}
+	t.Run("default setup", func(t *testing.T) {
+		e := newTestVMCLI(t)
+		e.runProg(t, "config")
+		check(t, e, false)
+	})
+	t.Run("setup with state", func(t *testing.T) {
+		e := newTestVMClIWithState(t)
+		e.runProg(t, "config", "exit")
+		check(t, e, true)
+	})
+}
+
 func TestDumpStorage(t *testing.T) {
 	e := newTestVMClIWithState(t)
 
@@ -1343,6 +2963,39 @@ func TestLoadtx(t *testing.T) {
 	e.checkError(t, errors.New("missing argument: <file-or-hash>"))
 }
 
+func TestContracts(t *testing.T) {
+	t.Run("no contracts", func(t *testing.T) {
+		e := newTestVMCLI(t)
+		e.runProg(t, "contracts")
+		e.checkNextLine(t, "no contracts deployed")
+	})
+
+	e := newTestVMClIWithState(t)
+	var (
+		ids    []int32
+		hashes []util.Uint160
+		names  []string
+	)
+	for id := int32(1); ; id++ {
+		h, err := e.cli.chain.GetContractScriptHash(id)
+		if err != nil {
+			break
+		}
+		cs := e.cli.chain.GetContractState(h)
+		require.NotNil(t, cs)
+		ids = append(ids, id)
+		hashes = append(hashes, h)
+		names = append(names, cs.Manifest.Name)
+	}
+	require.True(t, len(ids) > 1)
+
+	e.runProg(t, "contracts")
+	e.checkNextLine(t, "^ID\\s+HASH\\s+NAME\\n$")
+	for i := range ids {
+		e.checkNextLine(t, fmt.Sprintf("^%d\\s+0x%s\\s+%s\\s*$", ids[i], hashes[i].StringLE(), regexp.QuoteMeta(names[i])))
+	}
+}
+
 func TestLoaddeployed(t *testing.T) {
 	e := newTestVMClIWithState(t)
 
@@ -1,20 +1,42 @@
 package vm
 
 import (
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
+	"path/filepath"
 
 	"github.com/chzyer/readline"
 	"github.com/nspcc-dev/neo-go/cli/cmdargs"
 	"github.com/nspcc-dev/neo-go/cli/options"
 	"github.com/nspcc-dev/neo-go/pkg/core/storage/dbconfig"
 	"github.com/urfave/cli/v2"
+	"gopkg.in/yaml.v3"
 )
 
+// userConfigFile is the name of VM CLI user configuration file located in
+// neo-go subdirectory of the user configuration directory (like
+// ~/.config/neo-go/vmcli.yml).
+const userConfigFile = "vmcli.yml"
+
+// userConfig contains VM CLI defaults read from the user configuration file.
+type userConfig struct {
+	// Verbose enables verbose mode at startup (see 'verbose' command).
+	Verbose bool `yaml:"Verbose"`
+	// GasLimit is the GAS limit (in satoshi) used for loaded programs if
+	// --gas flag is not given, zero means no default.
+	GasLimit int64 `yaml:"GasLimit"`
+}
+
 // NewCommands returns 'vm' command.
 func NewCommands() []*cli.Command {
 	cfgFlags := []cli.Flag{options.Config, options.ConfigFile, options.RelativePath}
 	cfgFlags = append(cfgFlags, options.Network...)
+	cfgFlags = append(cfgFlags, &cli.BoolFlag{
+		Name:  "readonly",
+		Usage: "Forbid storage modifications made by the loaded programs",
+	})
 	return []*cli.Command{{
 		Name:   "vm",
 		Usage:  "Start the virtual machine",
@@ -32,7 +54,8 @@ func startVMPrompt(ctx *cli.Context) error {
 	if err != nil {
 		return cli.Exit(err, 1)
 	}
-	if ctx.NumFlags() == 0 {
+	readOnly := ctx.Bool("readonly")
+	if ctx.NumFlags() == 0 || (readOnly && ctx.NumFlags() == 1) {
 		cfg.ApplicationConfiguration.DBConfiguration.Type = dbconfig.InMemoryDB
 	}
 	if cfg.ApplicationConfiguration.DBConfiguration.Type != dbconfig.InMemoryDB {
@@ -40,9 +63,41 @@ func startVMPrompt(ctx *cli.Context) error {
 		cfg.ApplicationConfiguration.DBConfiguration.BoltDBOptions.ReadOnly = true
 	}
 
-	p, err := NewWithConfig(true, os.Exit, &readline.Config{}, cfg)
+	var userCfg userConfig
+	if dir, err := os.UserConfigDir(); err == nil { // No directory, no file.
+		userCfg, err = loadUserConfig(filepath.Join(dir, "neo-go", userConfigFile))
+		if err != nil {
+			return cli.Exit(fmt.Errorf("failed to load VM CLI user config: %w", err), 1)
+		}
+	}
+
+	p, err := NewWithOptions(true, os.Exit, &readline.Config{}, cfg, Options{
+		ReadOnly: readOnly,
+		Verbose:  userCfg.Verbose,
+		GasLimit: userCfg.GasLimit,
+	})
 	if err != nil {
 		return cli.Exit(fmt.Errorf("failed to create VM CLI: %w", err), 1)
 	}
 	return p.Run()
 }
+
+// loadUserConfig reads VM CLI user configuration file from the given path, zero
+// config is returned if there is no such file.
+func loadUserConfig(path string) (userConfig, error) {
+	var cfg userConfig
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return cfg, nil
+		}
+		return cfg, err
+	}
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return cfg, err
+	}
+	if cfg.GasLimit < 0 {
+		return cfg, fmt.Errorf("negative GasLimit: %d", cfg.GasLimit)
+	}
+	return cfg, nil
+}
@@ -3,6 +3,7 @@ package runtime
 import (
 	"encoding/json"
 	"math/big"
+	"strings"
 	"testing"
 
 	"github.com/nspcc-dev/neo-go/internal/random"
@@ -112,6 +113,22 @@ func TestLog(t *testing.T) {
 		require.Error(t, Log(ic))
 	})
 
+	t.Run("max message", func(t *testing.T) {
+		log, buf := newL(zapcore.InfoLevel)
+		ic := &interop.Context{Log: log, VM: vm.New()}
+		ic.VM.LoadScriptWithHash([]byte{1}, h, callflag.All)
+		msg := strings.Repeat("a", MaxNotificationSize)
+		ic.VM.Estack().PushVal(msg)
+		require.NoError(t, Log(ic))
+
+		ls := buf.Lines()
+		require.Equal(t, 1, len(ls))
+
+		var logMsg map[string]any
+		require.NoError(t, json.Unmarshal([]byte(ls[0]), &logMsg))
+		require.Equal(t, msg, logMsg["msg"])
+	})
+
 	t.Run("good", func(t *testing.T) {
 		log, buf := newL(zapcore.InfoLevel)
 		ic := &interop.Context{Log: log, VM: vm.New()}
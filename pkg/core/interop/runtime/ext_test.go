@@ -523,6 +523,28 @@ func TestGetInvocationCounter(t *testing.T) {
 	})
 }
 
+func TestGetScriptContainer(t *testing.T) {
+	v, ic, _ := createVM(t)
+	v.LoadScriptWithFlags([]byte{byte(opcode.RET)}, callflag.All)
+
+	t.Run("transaction fees", func(t *testing.T) {
+		tx := getSharpTestTx(random.Uint160())
+		tx.SystemFee = 123
+		tx.NetworkFee = 456
+		ic.Container = tx
+
+		require.NoError(t, runtime.GetScriptContainer(ic))
+		items := v.Estack().Pop().Array()
+		require.Equal(t, tx.ToStackItem().Value(), items)
+		require.EqualValues(t, 123, items[4].Value().(*big.Int).Int64()) // System fee.
+		require.EqualValues(t, 456, items[5].Value().(*big.Int).Int64()) // Network fee.
+	})
+	t.Run("no container", func(t *testing.T) {
+		ic.Container = nil
+		require.Error(t, runtime.GetScriptContainer(ic))
+	})
+}
+
 func TestGetNetwork(t *testing.T) {
 	bc, acc := chain.NewSingle(t)
 	e := neotest.NewExecutor(t, bc, acc, acc)
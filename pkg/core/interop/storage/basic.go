@@ -115,10 +115,10 @@ func getContextInternal(ic *interop.Context, isReadOnly bool) error {
 
 func putWithContext(ic *interop.Context, stc *Context, key []byte, value []byte) error {
 	if len(key) > limits.MaxStorageKeyLen {
-		return errors.New("key is too big")
+		return fmt.Errorf("key is too big: %d bytes (max %d)", len(key), limits.MaxStorageKeyLen)
 	}
 	if len(value) > limits.MaxStorageValueLen {
-		return errors.New("value is too big")
+		return fmt.Errorf("value is too big: %d bytes (max %d)", len(value), limits.MaxStorageValueLen)
 	}
 	if stc.ReadOnly {
 		return errors.New("storage.Context is read only")
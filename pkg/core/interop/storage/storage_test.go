@@ -1,6 +1,7 @@
 package storage_test
 
 import (
+	"fmt"
 	"math/big"
 	"strings"
 	"testing"
@@ -76,11 +77,11 @@ func TestPut(t *testing.T) {
 		})
 		t.Run("big key", func(t *testing.T) {
 			initVM(t, make([]byte, limits.MaxStorageKeyLen+1), []byte{1}, -1)
-			require.Error(t, istorage.Put(ic))
+			require.ErrorContains(t, istorage.Put(ic), "key is too big")
 		})
 		t.Run("big value", func(t *testing.T) {
 			initVM(t, []byte{1}, make([]byte, limits.MaxStorageValueLen+1), -1)
-			require.Error(t, istorage.Put(ic))
+			require.ErrorContains(t, istorage.Put(ic), fmt.Sprintf("value is too big: %d bytes", limits.MaxStorageValueLen+1))
 		})
 	})
 }
@@ -228,6 +229,24 @@ func TestFind(t *testing.T) {
 			stackitem.NewByteArray(skeys[9][2:]),
 		})
 	})
+	t.Run("nested keys order", func(t *testing.T) {
+		// Keys that are prefixes of each other, the same key written twice
+		// is returned once with the latest value.
+		nested := [][]byte{{0x0a, 0x01}, {0x0a, 0x00, 0x00}, {0x0a, 0x00}, {0x0a, 0x00, 0x00}}
+		for i := range nested {
+			context.DAO.PutStorageItem(id, nested[i], []byte{byte(i)})
+		}
+		testFind(t, []byte{0x0a}, istorage.FindKeysOnly|istorage.FindRemovePrefix, []stackitem.Item{
+			stackitem.NewByteArray([]byte{0x00}),
+			stackitem.NewByteArray([]byte{0x00, 0x00}),
+			stackitem.NewByteArray([]byte{0x01}),
+		})
+		testFind(t, []byte{0x0a}, istorage.FindValuesOnly|istorage.FindBackwards, []stackitem.Item{
+			stackitem.NewByteArray([]byte{0}),
+			stackitem.NewByteArray([]byte{3}),
+			stackitem.NewByteArray([]byte{2}),
+		})
+	})
 	t.Run("values only", func(t *testing.T) {
 		testFind(t, []byte{0x01}, istorage.FindValuesOnly, []stackitem.Item{
 			stackitem.NewByteArray(items[2]),
@@ -158,7 +158,7 @@ func (l *Ledger) getTransactionFromBlock(ic *interop.Context, params []stackitem
 		return stackitem.Null{}
 	}
 	if index >= uint32(len(block.Transactions)) {
-		panic("wrong transaction index")
+		panic(fmt.Sprintf("transaction index out of range: %d (block has %d transactions)", index, len(block.Transactions)))
 	}
 	return block.Transactions[index].ToStackItem()
 }
@@ -57,6 +57,10 @@ const (
 	contractDestroyNotificationName = "Destroy"
 )
 
+// ErrContractAlreadyExists is returned by Deploy if the contract with the same
+// hash is already deployed.
+var ErrContractAlreadyExists = errors.New("contract already exists")
+
 var (
 	errGasLimitExceeded = errors.New("gas limit exceeded")
 
@@ -257,6 +261,41 @@ func GetContractScriptHash(d *dao.Simple, managementID int32, id int32) (util.Ui
 	return util.Uint160DecodeBytesBE(si)
 }
 
+// GetContracts returns all deployed (non-native) contracts from the given DAO
+// ordered by their IDs. It iterates over the whole contract hash namespace, so
+// it's not exposed to smart contracts and is intended for debugging tools only.
+func GetContracts(d *dao.Simple, managementID int32) ([]*state.Contract, error) {
+	var (
+		hashes []util.Uint160
+		err    error
+	)
+	d.Seek(managementID, storage.SeekRange{Prefix: []byte{prefixContractHash}}, func(k, v []byte) bool {
+		if len(k) != 4 || binary.BigEndian.Uint32(k) >= math.MaxInt32 {
+			return true
+		}
+		var h util.Uint160
+		h, err = util.Uint160DecodeBytesBE(v)
+		if err != nil {
+			err = fmt.Errorf("invalid contract hash for ID %d: %w", binary.BigEndian.Uint32(k), err)
+			return false
+		}
+		hashes = append(hashes, h)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	res := make([]*state.Contract, 0, len(hashes))
+	for _, h := range hashes {
+		cs, err := GetContract(d, managementID, h)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get contract %s: %w", h.StringLE(), err)
+		}
+		res = append(res, cs)
+	}
+	return res, nil
+}
+
 func getLimitedSlice(arg stackitem.Item, maxLen int) ([]byte, error) {
 	_, isNull := arg.(stackitem.Null)
 	if isNull {
@@ -393,7 +432,9 @@ func markUpdated(d *dao.Simple, managementID int32, hash util.Uint160, cs *state
 }
 
 // Deploy creates a contract's hash/ID and saves a new contract into the given DAO.
-// It doesn't run _deploy method and doesn't emit notification.
+// It doesn't run _deploy method and doesn't emit notification. If the contract
+// with the same hash already exists, ErrContractAlreadyExists is returned and
+// the existing contract is left intact.
 func (m *Management) Deploy(ic *interop.Context, sender util.Uint160, neff *nef.File, manif *manifest.Manifest) (*state.Contract, error) {
 	h := state.CreateContractHash(sender, neff.Checksum, manif.Name)
 	if m.Policy.IsBlocked(ic.DAO, h) {
@@ -401,7 +442,7 @@ func (m *Management) Deploy(ic *interop.Context, sender util.Uint160, neff *nef.
 	}
 	_, err := GetContract(ic.DAO, m.ID, h)
 	if err == nil {
-		return nil, errors.New("contract already exists")
+		return nil, ErrContractAlreadyExists
 	}
 	id, err := m.getNextContractID(ic.DAO)
 	if err != nil {
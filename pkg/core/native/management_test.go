@@ -47,7 +47,7 @@ func TestDeployGetUpdateDestroyContract(t *testing.T) {
 
 	// Double deploy.
 	_, err = mgmt.Deploy(ic, sender, ne, manif)
-	require.Error(t, err)
+	require.ErrorIs(t, err, ErrContractAlreadyExists)
 
 	// Different sender.
 	sender2 := util.Uint160{3, 2, 1}
@@ -67,6 +67,10 @@ func TestDeployGetUpdateDestroyContract(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, contract, refContract)
 
+	contracts, err := GetContracts(d, mgmt.ID)
+	require.NoError(t, err)
+	require.Equal(t, []*state.Contract{contract, contract2}, contracts)
+
 	upContract, err := mgmt.Update(ic, h, ne, manif)
 	refContract.UpdateCounter++
 	require.NoError(t, err)
@@ -78,6 +82,9 @@ func TestDeployGetUpdateDestroyContract(t *testing.T) {
 	require.Error(t, err)
 	_, err = GetContractByID(d, mgmt.ID, contract.ID)
 	require.Error(t, err)
+	contracts, err = GetContracts(d, mgmt.ID)
+	require.NoError(t, err)
+	require.Equal(t, []*state.Contract{contract2}, contracts)
 }
 
 func TestManagement_Initialize(t *testing.T) {
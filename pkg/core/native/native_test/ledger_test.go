@@ -126,7 +126,15 @@ func TestLedger_GetTransactionFromBlock(t *testing.T) {
 		ledgerInvoker.InvokeAndCheck(t, check, "getTransactionFromBlock", int64(b.Index), int64(0))
 	})
 	t.Run("bad transaction index", func(t *testing.T) {
-		ledgerInvoker.InvokeFail(t, "", "getTransactionFromBlock", b.Hash(), int64(1))
+		ledgerInvoker.InvokeFail(t, "transaction index out of range: 1 (block has 1 transactions)", "getTransactionFromBlock", b.Hash(), int64(1))
+	})
+	t.Run("negative transaction index", func(t *testing.T) {
+		ledgerInvoker.InvokeFail(t, "bigint is not a uint64", "getTransactionFromBlock", b.Hash(), int64(-1))
+	})
+	t.Run("empty block", func(t *testing.T) {
+		empty := e.AddNewBlock(t)
+		require.Equal(t, 0, len(empty.Transactions))
+		ledgerInvoker.InvokeFail(t, "transaction index out of range: 0 (block has 0 transactions)", "getTransactionFromBlock", empty.Hash(), int64(0))
 	})
 	t.Run("bad block hash (>int64)", func(t *testing.T) {
 		ledgerInvoker.InvokeFail(t, "", "getTransactionFromBlock", b.Hash().BytesBE()[:10], int64(0))
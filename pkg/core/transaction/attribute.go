@@ -31,45 +31,44 @@ type attrJSON struct {
 	Type string `json:"type"`
 }
 
+// newAttrValue returns a constructor of the value for the given attribute
+// type and a flag denoting whether the type is known.
+func newAttrValue(t AttrType) (func() AttrValue, bool) {
+	if t >= ReservedLowerBound && t <= ReservedUpperBound {
+		return func() AttrValue { return new(Reserved) }, true
+	}
+	newValue, ok := attrTypes[t]
+	return newValue, ok
+}
+
 // DecodeBinary implements the Serializable interface.
 func (attr *Attribute) DecodeBinary(br *io.BinReader) {
 	attr.Type = AttrType(br.ReadB())
-
-	switch t := attr.Type; t {
-	case HighPriority:
+	if br.Err != nil {
 		return
-	case OracleResponseT:
-		attr.Value = new(OracleResponse)
-	case NotValidBeforeT:
-		attr.Value = new(NotValidBefore)
-	case ConflictsT:
-		attr.Value = new(Conflicts)
-	case NotaryAssistedT:
-		attr.Value = new(NotaryAssisted)
-	default:
-		if t >= ReservedLowerBound && t <= ReservedUpperBound {
-			attr.Value = new(Reserved)
-			break
-		}
+	}
+	newValue, ok := newAttrValue(attr.Type)
+	if !ok {
 		br.Err = fmt.Errorf("failed decoding TX attribute usage: 0x%2x", int(attr.Type))
 		return
 	}
+	if newValue == nil {
+		return
+	}
+	attr.Value = newValue()
 	attr.Value.DecodeBinary(br)
 }
 
 // EncodeBinary implements the Serializable interface.
 func (attr *Attribute) EncodeBinary(bw *io.BinWriter) {
 	bw.WriteB(byte(attr.Type))
-	switch t := attr.Type; t {
-	case HighPriority:
-	case OracleResponseT, NotValidBeforeT, ConflictsT, NotaryAssistedT:
-		attr.Value.EncodeBinary(bw)
-	default:
-		if t >= ReservedLowerBound && t <= ReservedUpperBound {
-			attr.Value.EncodeBinary(bw)
-			break
-		}
+	newValue, ok := newAttrValue(attr.Type)
+	if !ok {
 		bw.Err = fmt.Errorf("failed encoding TX attribute usage: 0x%2x", attr.Type)
+		return
+	}
+	if newValue != nil {
+		attr.Value.EncodeBinary(bw)
 	}
 }
 
@@ -89,28 +88,20 @@ func (attr *Attribute) UnmarshalJSON(data []byte) error {
 	if err != nil {
 		return err
 	}
-	switch aj.Type {
-	case HighPriority.String():
-		attr.Type = HighPriority
-		return nil
-	case OracleResponseT.String():
-		attr.Type = OracleResponseT
+	for t, newValue := range attrTypes {
+		if aj.Type != t.String() {
+			continue
+		}
+		attr.Type = t
+		if newValue == nil {
+			return nil
+		}
 		// Note: because `type` field will not be present in any attribute
 		// value, we can unmarshal the same data. The overhead is minimal.
-		attr.Value = new(OracleResponse)
-	case NotValidBeforeT.String():
-		attr.Type = NotValidBeforeT
-		attr.Value = new(NotValidBefore)
-	case ConflictsT.String():
-		attr.Type = ConflictsT
-		attr.Value = new(Conflicts)
-	case NotaryAssistedT.String():
-		attr.Type = NotaryAssistedT
-		attr.Value = new(NotaryAssisted)
-	default:
-		return errors.New("wrong Type")
+		attr.Value = newValue()
+		return json.Unmarshal(data, attr.Value)
 	}
-	return json.Unmarshal(data, attr.Value)
+	return errors.New("wrong Type")
 }
 
 // Copy creates a deep copy of the Attribute.
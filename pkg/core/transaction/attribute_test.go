@@ -114,6 +114,27 @@ func TestAttribute_EncodeBinary(t *testing.T) {
 			require.Error(t, testserdes.DecodeBinary(bw.Bytes(), new(NotaryAssisted)))
 		})
 	})
+	t.Run("sequence", func(t *testing.T) {
+		h := random.Uint256()
+		bw := io.NewBufBinWriter()
+		bw.WriteB(byte(NotValidBeforeT))
+		bw.WriteU32LE(123)
+		bw.WriteB(byte(ConflictsT))
+		bw.WriteBytes(h.BytesBE())
+		require.NoError(t, bw.Err)
+
+		br := io.NewBinReaderFromBuf(bw.Bytes())
+		var nvb, conflicts Attribute
+		nvb.DecodeBinary(br)
+		conflicts.DecodeBinary(br)
+		require.NoError(t, br.Err)
+		require.Equal(t, Attribute{Type: NotValidBeforeT, Value: &NotValidBefore{Height: 123}}, nvb)
+		require.Equal(t, Attribute{Type: ConflictsT, Value: &Conflicts{Hash: h}}, conflicts)
+	})
+	t.Run("unknown type", func(t *testing.T) {
+		err := testserdes.DecodeBinary([]byte{0x42, 1, 2, 3, 4}, new(Attribute))
+		require.ErrorContains(t, err, "failed decoding TX attribute usage")
+	})
 }
 
 func TestAttribute_MarshalJSON(t *testing.T) {
@@ -203,6 +224,9 @@ func TestAttribute_Copy(t *testing.T) {
 			case *OracleResponse:
 				originalVal.Result = append(originalVal.Result, 0xFF)
 				assert.NotEqual(t, len(original.Value.(*OracleResponse).Result), len(originalVal.Result))
+			case *NotValidBefore:
+				originalVal.Height++
+				assert.NotEqual(t, original.Value.(*NotValidBefore).Height, originalVal.Height)
 			case *Conflicts:
 				originalVal.Hash = random.Uint256()
 				assert.NotEqual(t, original.Value.(*Conflicts).Hash, originalVal.Hash)
@@ -214,3 +238,14 @@ func TestAttribute_Copy(t *testing.T) {
 		}
 	}
 }
+
+func TestNotValidBefore_Equals(t *testing.T) {
+	a := &NotValidBefore{Height: 123}
+	require.True(t, a.Equals(a))
+	require.True(t, a.Equals(&NotValidBefore{Height: 123}))
+	require.True(t, a.Equals(a.Copy().(*NotValidBefore)))
+	require.False(t, a.Equals(&NotValidBefore{Height: 124}))
+	require.False(t, a.Equals(nil))
+	require.False(t, (*NotValidBefore)(nil).Equals(a))
+	require.True(t, (*NotValidBefore)(nil).Equals(nil))
+}
@@ -21,13 +21,15 @@ const (
 	NotaryAssistedT AttrType = 0x22 // NotaryAssisted
 )
 
-// attrTypes contains a set of valid attribute types (does not include reserved attributes).
-var attrTypes = map[AttrType]struct{}{
-	HighPriority:    {},
-	OracleResponseT: {},
-	NotValidBeforeT: {},
-	ConflictsT:      {},
-	NotaryAssistedT: {},
+// attrTypes contains a set of valid attribute types (does not include reserved
+// attributes) with constructors of their values, types without value have nil
+// constructor. New attribute types should be registered here.
+var attrTypes = map[AttrType]func() AttrValue{
+	HighPriority:    nil,
+	OracleResponseT: func() AttrValue { return new(OracleResponse) },
+	NotValidBeforeT: func() AttrValue { return new(NotValidBefore) },
+	ConflictsT:      func() AttrValue { return new(Conflicts) },
+	NotaryAssistedT: func() AttrValue { return new(NotaryAssisted) },
 }
 
 func (a AttrType) allowMultiple() bool {
@@ -29,3 +29,12 @@ func (n *NotValidBefore) Copy() AttrValue {
 		Height: n.Height,
 	}
 }
+
+// Equals checks whether both attributes have the same height. Two nil
+// attributes are considered to be equal.
+func (n *NotValidBefore) Equals(other *NotValidBefore) bool {
+	if n == nil || other == nil {
+		return n == other
+	}
+	return n.Height == other.Height
+}
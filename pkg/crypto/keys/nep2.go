@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"math"
 
 	"github.com/nspcc-dev/neo-go/pkg/crypto/hash"
 	"github.com/nspcc-dev/neo-go/pkg/crypto/keys/internal/scrypt"
@@ -40,9 +41,34 @@ func NEP2ScryptParams() ScryptParams {
 	}
 }
 
+// Validate checks that scrypt parameters are acceptable for the scrypt KDF: N
+// must be a power of 2 greater than 1, R and P must be positive and the
+// memory/CPU cost they imply must not be too large.
+func (s ScryptParams) Validate() error {
+	if s.N <= 1 || s.N&(s.N-1) != 0 {
+		return fmt.Errorf("invalid scrypt N %d: must be > 1 and a power of 2", s.N)
+	}
+	if s.R <= 0 || s.P <= 0 {
+		return fmt.Errorf("invalid scrypt r %d or p %d: must be positive", s.R, s.P)
+	}
+	if uint64(s.R)*uint64(s.P) >= 1<<30 || s.R > math.MaxInt/128/s.P || s.R > math.MaxInt/256 || s.N > math.MaxInt/128/s.R {
+		return fmt.Errorf("scrypt parameters are too large: n %d, r %d, p %d", s.N, s.R, s.P)
+	}
+	return nil
+}
+
+// IsWeakerThan returns true if any of scrypt parameters is lower than the
+// corresponding parameter of other.
+func (s ScryptParams) IsWeakerThan(other ScryptParams) bool {
+	return s.N < other.N || s.R < other.R || s.P < other.P
+}
+
 // NEP2Encrypt encrypts a the PrivateKey using the given passphrase
 // under the NEP-2 standard.
 func NEP2Encrypt(priv *PrivateKey, passphrase string, params ScryptParams) (s string, err error) {
+	if err := params.Validate(); err != nil {
+		return s, err
+	}
 	address := priv.Address()
 
 	addrHash := hash.Checksum([]byte(address))
@@ -86,6 +112,9 @@ func NEP2Decrypt(key, passphrase string, params ScryptParams) (*PrivateKey, erro
 	if err := validateNEP2Format(b); err != nil {
 		return nil, err
 	}
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
 
 	addrHash := b[3:7]
 	// Normalize the passphrase according to the NFC standard.
@@ -55,6 +55,35 @@ func TestNEP2DecryptErrors(t *testing.T) {
 	s = "KxhEDBQyyEFymvfJD96q8stMbJMbZUb6D1PmXqBWZDU2WvbvVs9o"
 	_, err = NEP2Decrypt(s, p, NEP2ScryptParams())
 	assert.Error(t, err)
+
+	// Valid NEP-2, but invalid scrypt parameters.
+	_, err = NEP2Decrypt(keytestcases.Arr[0].EncryptedWif, p, ScryptParams{N: 2, R: 1})
+	assert.Error(t, err)
+}
+
+func TestScryptParamsValidate(t *testing.T) {
+	assert.NoError(t, NEP2ScryptParams().Validate())
+	assert.NoError(t, ScryptParams{N: 2, R: 1, P: 1}.Validate())
+	for _, sp := range []ScryptParams{
+		{},
+		{N: 0, R: 8, P: 8},
+		{N: 1, R: 8, P: 8},
+		{N: 1000, R: 8, P: 8},
+		{N: 16384, R: 0, P: 8},
+		{N: 16384, R: 8, P: 0},
+		{N: 16384, R: 1 << 15, P: 1 << 15},
+	} {
+		assert.Error(t, sp.Validate(), sp)
+	}
+}
+
+func TestScryptParamsIsWeakerThan(t *testing.T) {
+	base := ScryptParams{N: 16, R: 8, P: 8}
+	assert.False(t, base.IsWeakerThan(base))
+	assert.False(t, base.IsWeakerThan(ScryptParams{N: 2, R: 1, P: 1}))
+	assert.True(t, base.IsWeakerThan(ScryptParams{N: 32, R: 8, P: 8}))
+	assert.True(t, base.IsWeakerThan(ScryptParams{N: 16, R: 9, P: 8}))
+	assert.True(t, base.IsWeakerThan(ScryptParams{N: 2, R: 1, P: 9}))
 }
 
 func TestValidateNEP2Format(t *testing.T) {
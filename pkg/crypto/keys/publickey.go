@@ -18,6 +18,7 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/io"
 	"github.com/nspcc-dev/neo-go/pkg/util"
 	"github.com/nspcc-dev/neo-go/pkg/vm/emit"
+	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
 	"gopkg.in/yaml.v3"
 )
 
@@ -228,6 +229,25 @@ func decodeCompressedY(x *big.Int, ylsb uint, curve elliptic.Curve) (*big.Int, e
 	return y, nil
 }
 
+// ToStackItem implements stackitem.Convertible interface. The key is
+// represented as a ByteArray with its compressed encoding, nil key is
+// represented as Null.
+func (p *PublicKey) ToStackItem() (stackitem.Item, error) {
+	if p == nil {
+		return stackitem.Null{}, nil
+	}
+	return stackitem.NewByteArray(p.Bytes()), nil
+}
+
+// FromStackItem implements stackitem.Convertible interface.
+func (p *PublicKey) FromStackItem(item stackitem.Item) error {
+	b, err := item.TryBytes()
+	if err != nil {
+		return err
+	}
+	return p.DecodeBytes(b)
+}
+
 // DecodeBytes decodes a PublicKey from the given slice of bytes.
 func (p *PublicKey) DecodeBytes(data []byte) error {
 	b := io.NewBinReaderFromBuf(data)
@@ -10,6 +10,7 @@ import (
 	"testing"
 
 	"github.com/nspcc-dev/neo-go/internal/testserdes"
+	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
 	"github.com/stretchr/testify/require"
 	"gopkg.in/yaml.v3"
 )
@@ -40,6 +41,21 @@ func TestEncodeDecodePublicKey(t *testing.T) {
 	}
 }
 
+func TestPublicKey_ToStackItem(t *testing.T) {
+	k, err := NewPrivateKey()
+	require.NoError(t, err)
+	p := k.PublicKey()
+
+	item := stackitem.Make(p)
+	require.Equal(t, stackitem.NewByteArray(p.Bytes()), item)
+	require.Equal(t, 33, len(item.Value().([]byte)))
+	testserdes.ToFromStackItem(t, p, new(PublicKey))
+
+	require.Equal(t, stackitem.Null{}, stackitem.Make((*PublicKey)(nil)))
+	require.Error(t, new(PublicKey).FromStackItem(stackitem.NewArray(nil)))
+	require.Error(t, new(PublicKey).FromStackItem(stackitem.NewByteArray([]byte{0x02})))
+}
+
 func TestPublicKeys_Copy(t *testing.T) {
 	require.Nil(t, (PublicKeys)(nil).Copy())
 
@@ -8,6 +8,11 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/crypto/hash"
 )
 
+// Decode decodes the given base58-encoded string without any checksum check.
+func Decode(s string) ([]byte, error) {
+	return base58.Decode(s)
+}
+
 // CheckDecode implements base58-encoded string decoding with a hash-based
 // checksum check.
 func CheckDecode(s string) (b []byte, err error) {
@@ -20,6 +20,15 @@ func TestCheckEncodeDecode(t *testing.T) {
 	assert.Equal(t, decoded, b58CsumDecoded)
 }
 
+func TestDecode(t *testing.T) {
+	decoded, err := Decode("StV1DL6CwTryKyV")
+	require.NoError(t, err)
+	require.Equal(t, []byte("hello world"), decoded)
+
+	_, err = Decode("BASE%*")
+	require.Error(t, err)
+}
+
 func TestCheckDecodeFailures(t *testing.T) {
 	badbase58 := "BASE%*"
 	_, err := CheckDecode(badbase58)
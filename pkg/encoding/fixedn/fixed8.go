@@ -2,6 +2,8 @@ package fixedn
 
 import (
 	"cmp"
+	"errors"
+	"math"
 	"strconv"
 	"strings"
 
@@ -14,6 +16,10 @@ const (
 	decimals  = 100000000
 )
 
+// ErrOverflow is returned when the result of Fixed8 operation doesn't fit
+// into Fixed8.
+var ErrOverflow = errors.New("fixed8 overflow")
+
 // Fixed8 represents a fixed-point number with precision 10^-8.
 type Fixed8 int64
 
@@ -145,6 +151,36 @@ func (f Fixed8) Sub(g Fixed8) Fixed8 {
 	return f - g
 }
 
+// CheckedAdd returns f + g or ErrOverflow if the result doesn't fit into Fixed8.
+func (f Fixed8) CheckedAdd(g Fixed8) (Fixed8, error) {
+	if (g > 0 && f > math.MaxInt64-g) || (g < 0 && f < math.MinInt64-g) {
+		return 0, ErrOverflow
+	}
+	return f + g, nil
+}
+
+// CheckedSub returns f - g or ErrOverflow if the result doesn't fit into Fixed8.
+func (f Fixed8) CheckedSub(g Fixed8) (Fixed8, error) {
+	if (g < 0 && f > math.MaxInt64+g) || (g > 0 && f < math.MinInt64+g) {
+		return 0, ErrOverflow
+	}
+	return f - g, nil
+}
+
+// DivisibleBy checks whether f can be represented with the given number of
+// decimal places, i.e. it has no fractional component beyond 10^-prec. Any
+// value is divisible by precision of 8 and more.
+func (f Fixed8) DivisibleBy(prec byte) bool {
+	if prec >= precision {
+		return true
+	}
+	var div int64 = 1
+	for range precision - prec {
+		div *= 10
+	}
+	return int64(f)%div == 0
+}
+
 // LessThan implements Fixd8 < operator.
 func (f Fixed8) LessThan(g Fixed8) bool {
 	return f < g
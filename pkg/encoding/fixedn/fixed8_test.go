@@ -39,6 +39,76 @@ func TestFixed8Sub(t *testing.T) {
 	assert.Equal(t, int32(0), c.FractionalValue())
 }
 
+func TestFixed8CheckedAdd(t *testing.T) {
+	a := Fixed8FromInt64(1)
+	b := Fixed8FromInt64(2)
+
+	c, err := a.CheckedAdd(b)
+	assert.NoError(t, err)
+	assert.Equal(t, Fixed8FromInt64(3), c)
+
+	c, err = Fixed8(math.MaxInt64 - 1).CheckedAdd(Satoshi())
+	assert.NoError(t, err)
+	assert.Equal(t, Fixed8(math.MaxInt64), c)
+
+	_, err = Fixed8(math.MaxInt64).CheckedAdd(Satoshi())
+	assert.ErrorIs(t, err, ErrOverflow)
+
+	c, err = Fixed8(math.MinInt64 + 1).CheckedAdd(-Satoshi())
+	assert.NoError(t, err)
+	assert.Equal(t, Fixed8(math.MinInt64), c)
+
+	_, err = Fixed8(math.MinInt64).CheckedAdd(-Satoshi())
+	assert.ErrorIs(t, err, ErrOverflow)
+}
+
+func TestFixed8CheckedSub(t *testing.T) {
+	a := Fixed8FromInt64(42)
+	b := Fixed8FromInt64(34)
+
+	c, err := a.CheckedSub(b)
+	assert.NoError(t, err)
+	assert.Equal(t, Fixed8FromInt64(8), c)
+
+	c, err = Fixed8(math.MinInt64 + 1).CheckedSub(Satoshi())
+	assert.NoError(t, err)
+	assert.Equal(t, Fixed8(math.MinInt64), c)
+
+	_, err = Fixed8(math.MinInt64).CheckedSub(Satoshi())
+	assert.ErrorIs(t, err, ErrOverflow)
+
+	c, err = Fixed8(math.MaxInt64 - 1).CheckedSub(-Satoshi())
+	assert.NoError(t, err)
+	assert.Equal(t, Fixed8(math.MaxInt64), c)
+
+	_, err = Fixed8(math.MaxInt64).CheckedSub(-Satoshi())
+	assert.ErrorIs(t, err, ErrOverflow)
+}
+
+func TestFixed8DivisibleBy(t *testing.T) {
+	testCases := []struct {
+		value     Fixed8
+		precision byte
+		expected  bool
+	}{
+		{Fixed8FromInt64(5), 0, true},
+		{Fixed8FromInt64(-5), 0, true},
+		{Fixed8(510000000), 0, false},
+		{Fixed8(510000000), 1, true},
+		{Fixed8(512000000), 1, false},
+		{Fixed8(-512000000), 1, false},
+		{Fixed8(512000000), 2, true},
+		{Satoshi(), 7, false},
+		{Satoshi(), 8, true},
+		{Satoshi(), 9, true},
+		{Satoshi(), 255, true},
+		{0, 0, true},
+	}
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expected, tc.value.DivisibleBy(tc.precision), "%s with precision %d", tc.value, tc.precision)
+	}
+}
+
 func TestFixed8FromFloat(t *testing.T) {
 	inputs := []float64{12.98, 23.87654333, 100.654322, 456789.12345665, -3.14159265}
 
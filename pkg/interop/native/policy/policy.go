@@ -15,6 +15,8 @@ import (
 const Hash = "\x7b\xc6\x81\xc0\xa1\xf7\x1d\x54\x34\x57\xb6\x8b\xba\x8d\x5f\x9f\xdd\x4e\x5e\xcc"
 
 // GetFeePerByte represents `getFeePerByte` method of Policy native contract.
+// It returns the network fee per transaction byte in GAS fractions, use it
+// to estimate network fees from within a contract.
 func GetFeePerByte() int {
 	return neogointernal.CallWithToken(Hash, "getFeePerByte", int(contract.ReadStates)).(int)
 }
@@ -25,6 +27,8 @@ func SetFeePerByte(value int) {
 }
 
 // GetExecFeeFactor represents `getExecFeeFactor` method of Policy native contract.
+// It returns the factor opcode and syscall prices are multiplied by, use it
+// to estimate execution fees from within a contract.
 func GetExecFeeFactor() int {
 	return neogointernal.CallWithToken(Hash, "getExecFeeFactor", int(contract.ReadStates)).(int)
 }
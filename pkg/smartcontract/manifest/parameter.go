@@ -88,6 +88,24 @@ func (p Parameters) AreValid() error {
 	return nil
 }
 
+// ParameterIndex returns the index of the parameter with the given name in ps
+// or -1 if there is no such parameter.
+func ParameterIndex(ps []Parameter, name string) int {
+	return slices.IndexFunc(ps, func(p Parameter) bool {
+		return p.Name == name
+	})
+}
+
+// FindParameter returns the parameter with the given name from ps and true or
+// an empty parameter and false if there is no such parameter.
+func FindParameter(ps []Parameter, name string) (Parameter, bool) {
+	i := ParameterIndex(ps, name)
+	if i < 0 {
+		return Parameter{}, false
+	}
+	return ps[i], true
+}
+
 // sliceHasDups checks the slice for duplicate elements.
 func sliceHasDups[S ~[]E, E any](x S, cmp func(a, b E) int) bool {
 	if len(x) < 2 {
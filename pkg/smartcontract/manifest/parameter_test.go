@@ -32,6 +32,26 @@ func TestParametersAreValid(t *testing.T) {
 	require.Error(t, ps.AreValid())
 }
 
+func TestFindParameter(t *testing.T) {
+	ps := []Parameter{
+		NewParameter("from", smartcontract.Hash160Type),
+		NewParameter("to", smartcontract.Hash160Type),
+		NewParameter("amount", smartcontract.IntegerType),
+	}
+	require.Equal(t, 0, ParameterIndex(ps, "from"))
+	require.Equal(t, 2, ParameterIndex(ps, "amount"))
+	require.Equal(t, -1, ParameterIndex(ps, "data"))
+	require.Equal(t, -1, ParameterIndex(nil, "from"))
+
+	p, ok := FindParameter(ps, "to")
+	require.True(t, ok)
+	require.Equal(t, ps[1], p)
+
+	p, ok = FindParameter(ps, "data")
+	require.False(t, ok)
+	require.Equal(t, Parameter{}, p)
+}
+
 func TestParameter_ToStackItemFromStackItem(t *testing.T) {
 	p := &Parameter{
 		Name: "param",
@@ -65,7 +65,7 @@ func NewFile(script []byte) (*File, error) {
 		Script: script,
 	}
 	if len(file.Compiler) > compilerFieldSize {
-		return nil, errors.New("too long compiler field")
+		return nil, fmt.Errorf("too long compiler field: %d > %d", len(file.Compiler), compilerFieldSize)
 	}
 	file.Checksum = file.CalculateChecksum()
 	return file, nil
@@ -75,7 +75,7 @@ func NewFile(script []byte) (*File, error) {
 func (h *Header) EncodeBinary(w *io.BinWriter) {
 	w.WriteU32LE(h.Magic)
 	if len(h.Compiler) > compilerFieldSize {
-		w.Err = errors.New("invalid compiler name length")
+		w.Err = fmt.Errorf("invalid compiler name length: %d > %d", len(h.Compiler), compilerFieldSize)
 		return
 	}
 	var b = make([]byte, compilerFieldSize)
@@ -114,7 +114,7 @@ func (n *File) CalculateChecksum() uint32 {
 func (n *File) EncodeBinary(w *io.BinWriter) {
 	n.Header.EncodeBinary(w)
 	if len(n.Source) > MaxSourceURLLength {
-		w.Err = errors.New("source url too long")
+		w.Err = fmt.Errorf("source url too long: %d > %d", len(n.Source), MaxSourceURLLength)
 		return
 	}
 	w.WriteString(n.Source)
@@ -4,6 +4,7 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/nspcc-dev/neo-go/internal/random"
@@ -96,6 +97,29 @@ func TestEncodeDecodeBinary(t *testing.T) {
 	})
 }
 
+func TestEncodeBinaryLimits(t *testing.T) {
+	f := &File{
+		Header: Header{
+			Magic:    Magic,
+			Compiler: "best compiler version 1",
+		},
+		Tokens: []MethodToken{},
+		Script: []byte{1, 2, 3},
+	}
+	t.Run("compiler", func(t *testing.T) {
+		f := *f
+		f.Compiler = strings.Repeat("a", compilerFieldSize+1)
+		_, err := testserdes.EncodeBinary(&f)
+		require.ErrorContains(t, err, "invalid compiler name length: 65 > 64")
+	})
+	t.Run("source", func(t *testing.T) {
+		f := *f
+		f.Source = strings.Repeat("a", MaxSourceURLLength+1)
+		_, err := testserdes.EncodeBinary(&f)
+		require.ErrorContains(t, err, "source url too long: 257 > 256")
+	})
+}
+
 func checkDecodeError(t *testing.T, expected *File) {
 	bytes, err := testserdes.EncodeBinary(expected)
 	require.NoError(t, err)
@@ -552,6 +552,9 @@ func TestExpandParameterToEmitableToStackitem(t *testing.T) {
 	for _, errCase := range errCases {
 		_, err := ExpandParameterToEmitable(errCase)
 		require.Error(t, err)
+
+		_, err = errCase.ToStackItem()
+		require.ErrorIs(t, err, errors.ErrUnsupported)
 	}
 }
 
@@ -8,6 +8,7 @@ import (
 	"slices"
 
 	"github.com/nspcc-dev/neo-go/pkg/crypto/hash"
+	"github.com/nspcc-dev/neo-go/pkg/io"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract/callflag"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract/manifest"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract/nef"
@@ -230,6 +231,18 @@ func (c *Context) NextInstr() (int, opcode.Opcode) {
 	return c.nextip, op
 }
 
+// NextInstrParameter returns human-readable description of the next
+// instruction parameter (the same PrintOps shows). An empty string is returned
+// for instructions without parameters and instructions that can't be decoded.
+func (c *Context) NextInstrParameter() string {
+	ctx := &Context{sc: c.sc, nextip: c.nextip}
+	instr, parameter, err := ctx.Next()
+	if err != nil || parameter == nil {
+		return ""
+	}
+	return describeParameter(ctx, instr, parameter)
+}
+
 // GetCallFlags returns the calling flags which the context was created with.
 func (c *Context) GetCallFlags() callflag.CallFlag {
 	return c.sc.callFlag
@@ -340,3 +353,75 @@ func (c *Context) LocalsSlot() *Slot {
 func (c *Context) StaticsSlot() *Slot {
 	return &c.sc.static
 }
+
+// SerializeContext serializes the script, instruction pointers, the number of
+// return values, call flags, script hashes and slots (static, local and
+// arguments) of the given context into a slice of bytes. Evaluation and
+// exception handling stacks, NEF, manifest and the calling context are not
+// serialized. An error is returned if any of slot items can't be serialized
+// (like interop items or recursive arrays).
+func SerializeContext(ctx *Context) ([]byte, error) {
+	w := io.NewBufBinWriter()
+	w.WriteVarBytes(ctx.sc.prog)
+	w.WriteU32LE(uint32(ctx.ip))
+	w.WriteU32LE(uint32(ctx.nextip))
+	w.WriteU32LE(uint32(ctx.retCount))
+	w.WriteB(byte(ctx.sc.callFlag))
+	ctx.sc.scriptHash.EncodeBinary(w.BinWriter)
+	ctx.sc.callingScriptHash.EncodeBinary(w.BinWriter)
+	for _, s := range []Slot{ctx.sc.static, ctx.local, ctx.arguments} {
+		w.WriteBool(s != nil)
+		if s == nil {
+			continue
+		}
+		w.WriteVarUint(uint64(len(s)))
+		for i := range s {
+			stackitem.EncodeBinary(s.Get(i), w.BinWriter)
+		}
+	}
+	if w.Err != nil {
+		return nil, w.Err
+	}
+	return w.Bytes(), nil
+}
+
+// DeserializeContext restores the context previously serialized with
+// [SerializeContext]. The context returned is not bound to any VM, use
+// [VM.LoadContext] to continue its execution.
+func DeserializeContext(data []byte) (*Context, error) {
+	r := io.NewBinReaderFromBuf(data)
+	prog := r.ReadVarBytes()
+	ctx := NewContext(prog)
+	ctx.ip = int(r.ReadU32LE())
+	ctx.nextip = int(r.ReadU32LE())
+	ctx.retCount = int(int32(r.ReadU32LE()))
+	ctx.sc.callFlag = callflag.CallFlag(r.ReadB())
+	ctx.sc.scriptHash.DecodeBinary(r)
+	ctx.sc.callingScriptHash.DecodeBinary(r)
+	for _, s := range []*Slot{&ctx.sc.static, &ctx.local, &ctx.arguments} {
+		if !r.ReadBool() {
+			continue
+		}
+		n := r.ReadVarUint()
+		if r.Err == nil && n > MaxStackSize {
+			r.Err = fmt.Errorf("slot is too big: %d", n)
+		}
+		if r.Err != nil {
+			break
+		}
+		*s = make(Slot, n)
+		for i := range *s {
+			(*s)[i] = stackitem.DecodeBinary(r)
+		}
+	}
+	if r.Err != nil {
+		return nil, r.Err
+	}
+	if r.Len() != 0 {
+		return nil, errors.New("extra data")
+	}
+	if ctx.ip > len(prog) || ctx.nextip > len(prog) {
+		return nil, fmt.Errorf("instruction pointer is out of script bounds: %d/%d", ctx.nextip, len(prog))
+	}
+	return ctx, nil
+}
@@ -0,0 +1,79 @@
+package vm
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/callflag"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
+	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSerializeContext(t *testing.T) {
+	prog := []byte{
+		byte(opcode.INITSSLOT), 1, // 0
+		byte(opcode.PUSH7), byte(opcode.STSFLD0), // 2
+		byte(opcode.PUSH5), byte(opcode.INITSLOT), 1, 1, // 4
+		byte(opcode.PUSH3), byte(opcode.STLOC0), // 8
+		byte(opcode.NOP), // 10
+		byte(opcode.LDSFLD0), byte(opcode.LDLOC0), byte(opcode.ADD),
+		byte(opcode.LDARG0), byte(opcode.ADD), byte(opcode.RET),
+	}
+	v := newTestVM()
+	v.LoadScriptWithHash(prog, util.Uint160{1, 2, 3}, callflag.ReadStates)
+	v.AddBreakPoint(10)
+	require.NoError(t, v.Run())
+	require.Equal(t, 10, v.Context().NextIP())
+
+	data, err := SerializeContext(v.Context())
+	require.NoError(t, err)
+
+	ctx, err := DeserializeContext(data)
+	require.NoError(t, err)
+	require.Equal(t, prog, ctx.Program())
+	require.Equal(t, v.Context().IP(), ctx.IP())
+	require.Equal(t, 10, ctx.NextIP())
+	require.Equal(t, 1, ctx.retCount)
+	require.Equal(t, callflag.ReadStates, ctx.GetCallFlags())
+	require.Equal(t, util.Uint160{1, 2, 3}, ctx.ScriptHash())
+	require.Equal(t, Slot{stackitem.Make(7)}, *ctx.StaticsSlot())
+	require.Equal(t, Slot{stackitem.Make(3)}, *ctx.LocalsSlot())
+	require.Equal(t, Slot{stackitem.Make(5)}, *ctx.ArgumentsSlot())
+
+	restored := newTestVM()
+	restored.LoadContext(ctx)
+	require.NoError(t, restored.Run())
+	require.Equal(t, 1, restored.Estack().Len())
+	require.Equal(t, big.NewInt(15), restored.Estack().Pop().Value())
+
+	require.NoError(t, v.Run())
+	require.Equal(t, big.NewInt(15), v.Estack().Pop().Value())
+
+	t.Run("uninitialized slots", func(t *testing.T) {
+		v := load(makeProgram(opcode.NOP))
+		data, err := SerializeContext(v.Context())
+		require.NoError(t, err)
+		ctx, err := DeserializeContext(data)
+		require.NoError(t, err)
+		require.Nil(t, *ctx.StaticsSlot())
+		require.Nil(t, *ctx.LocalsSlot())
+		require.Nil(t, *ctx.ArgumentsSlot())
+		require.Equal(t, -1, ctx.retCount)
+	})
+	t.Run("interop in slot", func(t *testing.T) {
+		v := load([]byte{byte(opcode.INITSSLOT), 1, byte(opcode.STSFLD0), byte(opcode.RET)})
+		v.Estack().PushVal(stackitem.NewInterop(42))
+		require.NoError(t, v.StepInto())
+		require.NoError(t, v.StepInto())
+		_, err := SerializeContext(v.Context())
+		require.Error(t, err)
+	})
+	t.Run("bad data", func(t *testing.T) {
+		_, err := DeserializeContext(data[:len(data)-1])
+		require.Error(t, err)
+		_, err = DeserializeContext(append(data, 0))
+		require.Error(t, err)
+	})
+}
@@ -52,6 +52,7 @@ func TestRefCounterPopItem(t *testing.T) {
 	v := load(prog)
 	v.estack.PushVal(stackitem.NewArray([]stackitem.Item{stackitem.Make(42)}))
 	require.Equal(t, 2, int(v.refs))
+	require.Equal(t, 2, v.RefCount())
 	runVM(t, v)
 	require.Equal(t, 1, v.estack.Len())
 	require.Equal(t, 1, int(v.refs))
@@ -4,6 +4,7 @@ import (
 	"math/big"
 	"testing"
 
+	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
 	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
 	"github.com/stretchr/testify/require"
 )
@@ -25,3 +26,28 @@ func TestSlot_Get(t *testing.T) {
 	require.Equal(t, stackitem.NewBigInteger(big.NewInt(42)), s.Get(1))
 	require.Equal(t, 3, int(*rc))
 }
+
+func TestVM_ClearSlots(t *testing.T) {
+	v := load(makeProgram(opcode.NOP))
+	require.Equal(t, 0, v.ClearSlots())
+
+	ctx := v.Context()
+	ctx.sc.static.init(1, &v.refs)
+	ctx.local.init(2, &v.refs)
+	ctx.arguments.init(1, &v.refs)
+	ctx.sc.static.set(0, stackitem.NewArray([]stackitem.Item{stackitem.Make(1)}), &v.refs)
+	ctx.local.set(1, stackitem.Make(42), &v.refs)
+	require.Equal(t, 5, v.RefCount())
+
+	require.Equal(t, 2, v.ClearSlots())
+	require.Equal(t, 4, v.RefCount())
+	for _, s := range []Slot{ctx.sc.static, ctx.local, ctx.arguments} {
+		for i := range s {
+			require.Equal(t, stackitem.Null{}, s.Get(i))
+		}
+	}
+	require.Equal(t, 1, ctx.sc.static.Size())
+	require.Equal(t, 2, ctx.local.Size())
+	require.Equal(t, 1, ctx.arguments.Size())
+	require.Equal(t, 0, v.ClearSlots())
+}
@@ -0,0 +1,88 @@
+package stackitem
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+)
+
+// ErrInteropItem is returned by ToGo for Interop items (and containers
+// holding them) since their values are opaque and have no Go equivalent.
+var ErrInteropItem = errors.New("interop item can't be converted")
+
+// ToGo converts the item into Go-native values. It behaves as following:
+//
+//	BigInteger -> int64 if it fits, *big.Int otherwise
+//	ByteArray, Buffer -> []byte (a copy)
+//	Bool -> bool
+//	Null -> nil
+//	Array, Struct -> []any
+//	Map -> map[any]any with ByteArray keys converted to string
+//
+// Items referenced several times are converted once and share the result.
+// Circular references cause ErrRecursive, Interop items cause ErrInteropItem
+// and Pointers cause ErrInvalidType.
+func ToGo(item Item) (any, error) {
+	return toGo(item, make(map[Item]any), make(map[Item]bool))
+}
+
+func toGo(item Item, seen map[Item]any, path map[Item]bool) (any, error) {
+	if path[item] {
+		return nil, ErrRecursive
+	}
+	if v, ok := seen[item]; ok {
+		return v, nil
+	}
+	switch it := item.(type) {
+	case *Array, *Struct:
+		items := it.Value().([]Item)
+		res := make([]any, len(items))
+		path[item] = true
+		for i := range items {
+			v, err := toGo(items[i], seen, path)
+			if err != nil {
+				return nil, err
+			}
+			res[i] = v
+		}
+		delete(path, item)
+		seen[item] = res
+		return res, nil
+	case *Map:
+		res := make(map[any]any, len(it.value))
+		path[item] = true
+		for i := range it.value {
+			k, err := toGo(it.value[i].Key, seen, path)
+			if err != nil {
+				return nil, err
+			}
+			if b, ok := k.([]byte); ok {
+				k = string(b)
+			}
+			v, err := toGo(it.value[i].Value, seen, path)
+			if err != nil {
+				return nil, err
+			}
+			res[k] = v
+		}
+		delete(path, item)
+		seen[item] = res
+		return res, nil
+	case *BigInteger:
+		if it.Big().IsInt64() {
+			return it.Big().Int64(), nil
+		}
+		return new(big.Int).Set(it.Big()), nil
+	case *ByteArray, *Buffer:
+		return slices.Clone(it.Value().([]byte)), nil
+	case Bool:
+		return bool(it), nil
+	case Null:
+		return nil, nil
+	case *Interop:
+		return nil, ErrInteropItem
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrInvalidType, item.Type())
+	}
+}
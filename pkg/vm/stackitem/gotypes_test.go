@@ -0,0 +1,88 @@
+package stackitem
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestToGo(t *testing.T) {
+	bigVal, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+	testCases := map[string]struct {
+		item     Item
+		expected any
+	}{
+		"small integer":    {Make(-42), int64(-42)},
+		"big integer":      {NewBigInteger(bigVal), bigVal},
+		"byte array":       {Make([]byte{1, 2, 3}), []byte{1, 2, 3}},
+		"empty byte array": {Make([]byte{}), []byte{}},
+		"buffer":           {NewBuffer([]byte{4, 5}), []byte{4, 5}},
+		"bool":             {Make(true), true},
+		"null":             {Null{}, nil},
+		"array":            {Make([]Item{Make(1), Make("a")}), []any{int64(1), []byte("a")}},
+		"struct":           {NewStruct([]Item{Make(false), Null{}}), []any{false, nil}},
+		"map": {NewMapWithValue([]MapElement{
+			{Key: Make("key"), Value: Make(1)},
+			{Key: Make(2), Value: Make(true)},
+			{Key: Make(false), Value: Null{}},
+		}), map[any]any{"key": int64(1), int64(2): true, false: nil}},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			actual, err := ToGo(tc.item)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, actual)
+		})
+	}
+
+	t.Run("nested", func(t *testing.T) {
+		shared := NewArray([]Item{Make(7)})
+		m := NewMap()
+		m.Add(Make("inner"), NewStruct([]Item{shared, NewBigInteger(bigVal)}))
+		item := NewArray([]Item{
+			shared,
+			NewArray([]Item{NewArray([]Item{m, NewBuffer([]byte{0xff})})}),
+			Null{},
+		})
+
+		actual, err := ToGo(item)
+		require.NoError(t, err)
+		require.Equal(t, []any{
+			[]any{int64(7)},
+			[]any{[]any{
+				map[any]any{"inner": []any{[]any{int64(7)}, bigVal}},
+				[]byte{0xff},
+			}},
+			nil,
+		}, actual)
+	})
+	t.Run("copy", func(t *testing.T) {
+		b := NewBuffer([]byte{1})
+		actual, err := ToGo(b)
+		require.NoError(t, err)
+		actual.([]byte)[0] = 2
+		require.Equal(t, []byte{1}, b.Value())
+	})
+	t.Run("recursive", func(t *testing.T) {
+		arr := NewArray(nil)
+		arr.Append(NewStruct([]Item{arr}))
+		_, err := ToGo(arr)
+		require.ErrorIs(t, err, ErrRecursive)
+
+		m := NewMap()
+		m.Add(Make(1), m)
+		_, err = ToGo(m)
+		require.ErrorIs(t, err, ErrRecursive)
+	})
+	t.Run("interop", func(t *testing.T) {
+		_, err := ToGo(NewInterop(42))
+		require.ErrorIs(t, err, ErrInteropItem)
+		_, err = ToGo(NewArray([]Item{Make(1), NewInterop(42)}))
+		require.ErrorIs(t, err, ErrInteropItem)
+	})
+	t.Run("pointer", func(t *testing.T) {
+		_, err := ToGo(NewPointer(0, []byte{1}))
+		require.ErrorIs(t, err, ErrInvalidType)
+	})
+}
@@ -94,6 +94,8 @@ func mkInvConversion(from Item, to Type) error {
 }
 
 // TryMake tries to make an appropriate stack item from the provided value.
+// util.Uint160 and util.Uint256 are converted to big-endian ByteArrays,
+// Convertible values (like keys.PublicKey) are converted via ToStackItem.
 func TryMake(v any) (Item, error) {
 	switch val := v.(type) {
 	case int:
@@ -159,6 +161,8 @@ func TryMake(v any) (Item, error) {
 			return Null{}, nil
 		}
 		return TryMake(*val)
+	case Convertible:
+		return val.ToStackItem()
 	case nil:
 		return Null{}, nil
 	default:
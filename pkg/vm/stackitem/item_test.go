@@ -90,6 +90,18 @@ var makeStackItemTestCases = []struct {
 		input:  &util.Uint256{1, 2, 3},
 		result: NewByteArray(util.Uint256{1, 2, 3}.BytesBE()),
 	},
+	{
+		input:  util.Uint160{1, 2, 3},
+		result: NewByteArray(util.Uint160{1, 2, 3}.BytesBE()),
+	},
+	{
+		input:  util.Uint256{1, 2, 3},
+		result: NewByteArray(util.Uint256{1, 2, 3}.BytesBE()),
+	},
+	{
+		input:  &testConvertible{value: 42},
+		result: Make(42),
+	},
 	{
 		input:  (*util.Uint160)(nil),
 		result: Null{},
@@ -108,6 +120,23 @@ var makeStackItemErrorCases = []struct {
 	},
 }
 
+type testConvertible struct {
+	value int64
+}
+
+func (c *testConvertible) ToStackItem() (Item, error) {
+	return Make(c.value), nil
+}
+
+func (c *testConvertible) FromStackItem(item Item) error {
+	bi, err := item.TryInteger()
+	if err != nil {
+		return err
+	}
+	c.value = bi.Int64()
+	return nil
+}
+
 func TestMakeStackItem(t *testing.T) {
 	for _, testCase := range makeStackItemTestCases {
 		assert.Equal(t, testCase.result, Make(testCase.input))
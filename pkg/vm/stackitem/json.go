@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	gio "io"
+	"math"
 	"math/big"
 	"strconv"
 	"strings"
@@ -320,19 +321,52 @@ func (d *decoder) decodeMap() (*Map, error) {
 
 // ToJSONWithTypes serializes any stackitem to JSON in a lossless way.
 func ToJSONWithTypes(item Item) ([]byte, error) {
-	return toJSONWithTypes(nil, item, make(map[Item]sliceNoPointer, typicalNumOfItems))
+	e := typedJSONEncoder{
+		seen:     make(map[Item]sliceNoPointer, typicalNumOfItems),
+		maxDepth: math.MaxInt,
+		maxSize:  MaxSize,
+	}
+	return e.encode(nil, item, 0)
+}
+
+// ToJSONWithTypesLimited serializes any stackitem to JSON in a lossless way
+// checking the result against the provided limits. maxDepth is the maximum
+// allowed nesting level of compound items (Array, Struct and Map), maxSize is
+// the maximum allowed size of the resulting JSON in bytes. ErrTooDeep or
+// ErrTooBig are returned if the item doesn't fit into these limits.
+func ToJSONWithTypesLimited(item Item, maxDepth, maxSize int) ([]byte, error) {
+	e := typedJSONEncoder{
+		seen:     make(map[Item]sliceNoPointer, typicalNumOfItems),
+		maxDepth: maxDepth,
+		maxSize:  maxSize,
+	}
+	data, err := e.encode(nil, item, 0)
+	switch {
+	case errors.Is(err, ErrTooDeep):
+		return nil, fmt.Errorf("%w: nesting level exceeds %d", err, maxDepth)
+	case errors.Is(err, errTooBigSize):
+		return nil, fmt.Errorf("%w: JSON exceeds %d bytes", err, maxSize)
+	}
+	return data, err
+}
+
+// typedJSONEncoder holds the state of typed JSON serialization.
+type typedJSONEncoder struct {
+	seen     map[Item]sliceNoPointer
+	maxDepth int
+	maxSize  int
 }
 
-func toJSONWithTypes(data []byte, item Item, seen map[Item]sliceNoPointer) ([]byte, error) {
+func (e *typedJSONEncoder) encode(data []byte, item Item, depth int) ([]byte, error) {
 	if item == nil {
 		return nil, fmt.Errorf("%w: nil", ErrUnserializable)
 	}
-	if old, ok := seen[item]; ok {
+	if old, ok := e.seen[item]; ok {
 		if old.end == 0 {
 			// Compound item marshaling which has not yet finished.
 			return nil, ErrRecursive
 		}
-		if len(data)+old.end-old.start > MaxSize {
+		if len(data)+old.end-old.start > e.maxSize {
 			return nil, errTooBigSize
 		}
 		return append(data, data[old.start:old.end]...), nil
@@ -350,7 +384,7 @@ func toJSONWithTypes(data []byte, item Item, seen map[Item]sliceNoPointer) ([]by
 		hasValue = true
 	}
 
-	if len(data)+len(val) > MaxSize {
+	if len(data)+len(val) > e.maxSize {
 		return nil, errTooBigSize
 	}
 
@@ -369,13 +403,16 @@ func toJSONWithTypes(data []byte, item Item, seen map[Item]sliceNoPointer) ([]by
 
 	switch it := item.(type) {
 	case *Array, *Struct:
-		seen[item] = sliceNoPointer{}
+		if depth >= e.maxDepth {
+			return nil, ErrTooDeep
+		}
+		e.seen[item] = sliceNoPointer{}
 		data = append(data, '[')
 		for i, elem := range it.Value().([]Item) {
 			if i != 0 {
 				data = append(data, ',')
 			}
-			data, err = toJSONWithTypes(data, elem, seen)
+			data, err = e.encode(data, elem, depth+1)
 			if err != nil {
 				return nil, err
 			}
@@ -394,19 +431,22 @@ func toJSONWithTypes(data []byte, item Item, seen map[Item]sliceNoPointer) ([]by
 	case *BigInteger:
 		primitive = `"` + it.Big().String() + `"`
 	case *Map:
-		seen[item] = sliceNoPointer{}
+		if depth >= e.maxDepth {
+			return nil, ErrTooDeep
+		}
+		e.seen[item] = sliceNoPointer{}
 		data = append(data, '[')
 		for i := range it.value {
 			if i != 0 {
 				data = append(data, ',')
 			}
 			data = append(data, `{"key":`...)
-			data, err = toJSONWithTypes(data, it.value[i].Key, seen)
+			data, err = e.encode(data, it.value[i].Key, depth+1)
 			if err != nil {
 				return nil, err
 			}
 			data = append(data, `,"value":`...)
-			data, err = toJSONWithTypes(data, it.value[i].Value, seen)
+			data, err = e.encode(data, it.value[i].Value, depth+1)
 			if err != nil {
 				return nil, err
 			}
@@ -416,22 +456,22 @@ func toJSONWithTypes(data []byte, item Item, seen map[Item]sliceNoPointer) ([]by
 		primitive = strconv.Itoa(it.pos)
 	}
 	if len(primitive) != 0 {
-		if len(data)+len(primitive)+1 > MaxSize {
+		if len(data)+len(primitive)+1 > e.maxSize {
 			return nil, errTooBigSize
 		}
 		data = append(data, primitive...)
 		data = append(data, '}')
 
 		if isBuffer {
-			seen[item] = sliceNoPointer{start: start, end: len(data)}
+			e.seen[item] = sliceNoPointer{start: start, end: len(data)}
 		}
 	} else {
-		if len(data)+2 > MaxSize { // also take care of '}'
+		if len(data)+2 > e.maxSize { // also take care of '}'
 			return nil, errTooBigSize
 		}
 		data = append(data, ']', '}')
 
-		seen[item] = sliceNoPointer{start: start, end: len(data)}
+		e.seen[item] = sliceNoPointer{start: start, end: len(data)}
 	}
 	return data, nil
 }
@@ -435,6 +435,34 @@ func TestToJSONWithTypesBadCases(t *testing.T) {
 	})
 }
 
+func TestToJSONWithTypesLimited(t *testing.T) {
+	t.Run("depth", func(t *testing.T) {
+		var item Item = NewBool(true)
+		for range 3 {
+			item = NewArray([]Item{item})
+		}
+		_, err := ToJSONWithTypesLimited(item, 3, MaxSize)
+		require.NoError(t, err)
+		_, err = ToJSONWithTypesLimited(item, 2, MaxSize)
+		require.ErrorIs(t, err, ErrTooDeep)
+
+		m := NewMapWithValue([]MapElement{{NewBool(true), NewArray(nil)}})
+		_, err = ToJSONWithTypesLimited(m, 1, MaxSize)
+		require.ErrorIs(t, err, ErrTooDeep)
+	})
+	t.Run("size", func(t *testing.T) {
+		item := NewArray([]Item{NewByteArray(make([]byte, 64))})
+		data, err := ToJSONWithTypesLimited(item, MaxDeserialized, MaxSize)
+		require.NoError(t, err)
+		_, err = ToJSONWithTypesLimited(item, MaxDeserialized, len(data))
+		require.NoError(t, err)
+		_, err = ToJSONWithTypesLimited(item, MaxDeserialized, len(data)-1)
+		require.ErrorIs(t, err, ErrTooBig)
+		_, err = ToJSONWithTypesLimited(item, MaxDeserialized, 32)
+		require.ErrorIs(t, err, ErrTooBig)
+	})
+}
+
 func TestFromJSONWithTypes(t *testing.T) {
 	testCases := []struct {
 		name string
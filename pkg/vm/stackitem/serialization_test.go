@@ -263,6 +263,22 @@ func TestMapDeserializationError(t *testing.T) {
 	require.ErrorIs(t, err, ErrInvalidType)
 }
 
+func TestMapDeserializationOrder(t *testing.T) {
+	m := NewMap()
+	m.Add(Make(3), Make("c"))
+	m.Add(Make("b"), Make(2))
+	m.Add(Make(1), Make("a"))
+
+	data, err := Serialize(m)
+	require.NoError(t, err)
+	first, err := Deserialize(data)
+	require.NoError(t, err)
+	second, err := Deserialize(data)
+	require.NoError(t, err)
+	require.Equal(t, m.Value(), first.Value())
+	require.Equal(t, first.Value(), second.Value())
+}
+
 func TestDeserializeTooManyElements(t *testing.T) {
 	item := Make(0)
 	for range MaxDeserialized - 1 { // 1 for zero inner element.
@@ -0,0 +1,51 @@
+package stackitem
+
+// Stats contains the summary of the item structure.
+type Stats struct {
+	// Types is the number of items of every type found.
+	Types map[Type]int
+	// Total is the total number of items including the top-level one.
+	Total int
+	// MaxDepth is the maximum nesting level, primitive item has depth 1.
+	MaxDepth int
+	// Circular is true if the item contains circular references. Such
+	// references are not followed and not counted.
+	Circular bool
+}
+
+// GetStats walks the item recursively and returns its Stats. Items that
+// are referenced several times are counted every time they're encountered
+// (the same way they're treated by serialization).
+func GetStats(item Item) Stats {
+	s := Stats{Types: make(map[Type]int)}
+	s.walk(item, 1, make(map[Item]bool))
+	return s
+}
+
+func (s *Stats) walk(item Item, depth int, path map[Item]bool) {
+	if item == nil {
+		return
+	}
+	if path[item] {
+		s.Circular = true
+		return
+	}
+	s.Types[item.Type()]++
+	s.Total++
+	s.MaxDepth = max(s.MaxDepth, depth)
+	switch it := item.(type) {
+	case *Array, *Struct:
+		path[item] = true
+		for _, elem := range it.Value().([]Item) {
+			s.walk(elem, depth+1, path)
+		}
+		delete(path, item)
+	case *Map:
+		path[item] = true
+		for _, elem := range it.value {
+			s.walk(elem.Key, depth+1, path)
+			s.walk(elem.Value, depth+1, path)
+		}
+		delete(path, item)
+	}
+}
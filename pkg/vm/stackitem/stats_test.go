@@ -0,0 +1,46 @@
+package stackitem
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetStats(t *testing.T) {
+	t.Run("primitive", func(t *testing.T) {
+		s := GetStats(Make(42))
+		require.Equal(t, map[Type]int{IntegerT: 1}, s.Types)
+		require.Equal(t, 1, s.Total)
+		require.Equal(t, 1, s.MaxDepth)
+		require.False(t, s.Circular)
+	})
+	t.Run("nested", func(t *testing.T) {
+		shared := Make("ab")
+		m := NewMap()
+		m.Add(Make(1), NewArray([]Item{shared, Null{}}))
+		item := NewStruct([]Item{shared, m, Make(true)})
+
+		s := GetStats(item)
+		require.Equal(t, map[Type]int{
+			StructT:    1,
+			MapT:       1,
+			ArrayT:     1,
+			ByteArrayT: 2,
+			IntegerT:   1,
+			AnyT:       1,
+			BooleanT:   1,
+		}, s.Types)
+		require.Equal(t, 8, s.Total)
+		require.Equal(t, 4, s.MaxDepth)
+		require.False(t, s.Circular)
+	})
+	t.Run("circular", func(t *testing.T) {
+		arr := NewArray([]Item{Make(1)})
+		arr.Append(arr)
+
+		s := GetStats(arr)
+		require.Equal(t, map[Type]int{ArrayT: 1, IntegerT: 1}, s.Types)
+		require.Equal(t, 2, s.MaxDepth)
+		require.True(t, s.Circular)
+	})
+}
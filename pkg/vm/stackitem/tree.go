@@ -0,0 +1,64 @@
+package stackitem
+
+import (
+	"encoding/hex"
+	"strconv"
+	"strings"
+)
+
+// treeIndent is used to indent nested items in ToTreeString output.
+const treeIndent = "    "
+
+// ToTreeString returns human-readable representation of the item as an
+// indented tree with a type and a value per line. Compound items are printed
+// with the number of their elements followed by the elements themselves,
+// byte strings and buffers are hex-encoded. Circular references are
+// replaced by a "<circular reference>" line.
+func ToTreeString(item Item) string {
+	var b strings.Builder
+	writeTree(&b, item, "", "", make(map[Item]bool))
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+func writeTree(b *strings.Builder, item Item, indent string, prefix string, path map[Item]bool) {
+	b.WriteString(indent)
+	b.WriteString(prefix)
+	if item == nil {
+		b.WriteString("<nil>\n")
+		return
+	}
+	if path[item] {
+		b.WriteString("<circular reference>\n")
+		return
+	}
+	b.WriteString(item.Type().String())
+	switch it := item.(type) {
+	case *Array, *Struct:
+		elems := it.Value().([]Item)
+		b.WriteString(" (" + strconv.Itoa(len(elems)) + ")\n")
+		path[item] = true
+		for _, elem := range elems {
+			writeTree(b, elem, indent+treeIndent, "", path)
+		}
+		delete(path, item)
+		return
+	case *Map:
+		b.WriteString(" (" + strconv.Itoa(len(it.value)) + ")\n")
+		path[item] = true
+		for _, elem := range it.value {
+			writeTree(b, elem.Key, indent+treeIndent, "key: ", path)
+			writeTree(b, elem.Value, indent+treeIndent, "value: ", path)
+		}
+		delete(path, item)
+		return
+	case *BigInteger:
+		b.WriteString(" " + it.Big().String())
+	case Bool:
+		b.WriteString(" " + strconv.FormatBool(bool(it)))
+	case *ByteArray, *Buffer:
+		b.WriteString(" " + hex.EncodeToString(it.Value().([]byte)))
+	case *Pointer:
+		b.WriteString(" " + strconv.Itoa(it.pos))
+	}
+	b.WriteString("\n")
+}
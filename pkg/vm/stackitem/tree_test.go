@@ -0,0 +1,63 @@
+package stackitem
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestToTreeString(t *testing.T) {
+	t.Run("primitive", func(t *testing.T) {
+		require.Equal(t, "Integer 42", ToTreeString(Make(42)))
+		require.Equal(t, "Boolean true", ToTreeString(Make(true)))
+		require.Equal(t, "ByteString 010203", ToTreeString(NewByteArray([]byte{1, 2, 3})))
+		require.Equal(t, "Buffer 0102", ToTreeString(NewBuffer([]byte{1, 2})))
+		require.Equal(t, "Any", ToTreeString(Null{}))
+		require.Equal(t, "InteropInterface", ToTreeString(NewInterop(nil)))
+	})
+	t.Run("nested array", func(t *testing.T) {
+		item := NewArray([]Item{
+			Make(1),
+			NewStruct([]Item{Make("ab"), NewArray(nil)}),
+			Make(false),
+		})
+		expected := `Array (3)
+    Integer 1
+    Struct (2)
+        ByteString 6162
+        Array (0)
+    Boolean false`
+		require.Equal(t, expected, ToTreeString(item))
+	})
+	t.Run("map", func(t *testing.T) {
+		m := NewMap()
+		m.Add(Make(1), Make("a"))
+		m.Add(Make("key"), NewArray([]Item{Make(2), Null{}}))
+		expected := `Map (2)
+    key: Integer 1
+    value: ByteString 61
+    key: ByteString 6b6579
+    value: Array (2)
+        Integer 2
+        Any`
+		require.Equal(t, expected, ToTreeString(m))
+	})
+	t.Run("circular reference", func(t *testing.T) {
+		arr := NewArray([]Item{Make(1)})
+		arr.Append(arr)
+		expected := `Array (2)
+    Integer 1
+    <circular reference>`
+		require.Equal(t, expected, ToTreeString(arr))
+	})
+	t.Run("repeated item", func(t *testing.T) {
+		inner := NewArray([]Item{Make(1)})
+		arr := NewArray([]Item{inner, inner})
+		expected := `Array (2)
+    Array (1)
+        Integer 1
+    Array (1)
+        Integer 1`
+		require.Equal(t, expected, ToTreeString(arr))
+	})
+}
@@ -168,6 +168,34 @@ func (v *VM) GasConsumed() int64 {
 	return v.gasConsumed
 }
 
+// RefCount returns the number of stack item references currently held by the VM
+// (including nested compound items), this value is limited by MaxStackSize.
+func (v *VM) RefCount() int {
+	return int(v.refs)
+}
+
+// ClearSlots sets all variables of the current context's static, local and
+// argument slots to Null keeping slot sizes intact. It returns the number of
+// non-Null items removed.
+func (v *VM) ClearSlots() int {
+	var (
+		ctx = v.Context()
+		n   int
+	)
+	if ctx == nil {
+		return 0
+	}
+	for _, s := range []Slot{ctx.sc.static, ctx.local, ctx.arguments} {
+		for i := range s {
+			if s[i] != nil {
+				n++
+			}
+			s.set(i, nil, &v.refs)
+		}
+	}
+	return n
+}
+
 // AddGas consumes the specified amount of gas. It returns true if gas limit wasn't exceeded.
 func (v *VM) AddGas(gas int64) bool {
 	v.gasConsumed += gas
@@ -186,6 +214,13 @@ func (v *VM) Istack() []*Context {
 
 // PrintOps prints the opcodes of the current loaded program to stdout.
 func (v *VM) PrintOps(out io.Writer) {
+	v.PrintOpsRange(out, 0, 0)
+}
+
+// PrintOpsRange is similar to PrintOps, but prints only count instructions
+// starting from the one at the specified index. Non-positive count means that
+// all instructions till the end of the program are printed.
+func (v *VM) PrintOpsRange(out io.Writer, start, count int) {
 	if out == nil {
 		out = os.Stdout
 	}
@@ -193,9 +228,16 @@ func (v *VM) PrintOps(out io.Writer) {
 	fmt.Fprintln(w, "INDEX\tOPCODE\tPARAMETER")
 	realctx := v.Context()
 	ctx := &Context{sc: realctx.sc}
-	for {
+	for printed := 0; count <= 0 || printed < count; {
 		cursor := ""
 		instr, parameter, err := ctx.Next()
+		if ctx.ip < start && err == nil {
+			if ctx.nextip >= len(ctx.sc.prog) {
+				break
+			}
+			continue
+		}
+		printed++
 		if ctx.ip == realctx.ip {
 			cursor = "\t<<"
 		}
@@ -205,52 +247,7 @@ func (v *VM) PrintOps(out io.Writer) {
 		}
 		var desc = ""
 		if parameter != nil {
-			switch instr {
-			case opcode.JMP, opcode.JMPIF, opcode.JMPIFNOT, opcode.CALL,
-				opcode.JMPEQ, opcode.JMPNE,
-				opcode.JMPGT, opcode.JMPGE, opcode.JMPLE, opcode.JMPLT,
-				opcode.JMPL, opcode.JMPIFL, opcode.JMPIFNOTL, opcode.CALLL,
-				opcode.JMPEQL, opcode.JMPNEL,
-				opcode.JMPGTL, opcode.JMPGEL, opcode.JMPLEL, opcode.JMPLTL,
-				opcode.PUSHA, opcode.ENDTRY, opcode.ENDTRYL:
-				desc = getOffsetDesc(ctx, parameter)
-			case opcode.TRY, opcode.TRYL:
-				catchP, finallyP := getTryParams(instr, parameter)
-				desc = fmt.Sprintf("catch %s, finally %s",
-					getOffsetDesc(ctx, catchP), getOffsetDesc(ctx, finallyP))
-			case opcode.INITSSLOT:
-				desc = fmt.Sprint(parameter[0])
-			case opcode.CONVERT, opcode.ISTYPE:
-				typ := stackitem.Type(parameter[0])
-				desc = fmt.Sprintf("%s (%x)", typ, parameter[0])
-			case opcode.INITSLOT:
-				desc = fmt.Sprintf("%d local, %d arg", parameter[0], parameter[1])
-			case opcode.SYSCALL:
-				name, err := interopnames.FromID(GetInteropID(parameter))
-				if err != nil {
-					name = "not found"
-				}
-				desc = fmt.Sprintf("%s (%x)", name, parameter)
-			case opcode.PUSHINT8, opcode.PUSHINT16, opcode.PUSHINT32,
-				opcode.PUSHINT64, opcode.PUSHINT128, opcode.PUSHINT256:
-				val := bigint.FromBytes(parameter)
-				desc = fmt.Sprintf("%d (%x)", val, parameter)
-			case opcode.LDLOC, opcode.STLOC, opcode.LDARG, opcode.STARG, opcode.LDSFLD, opcode.STSFLD:
-				desc = fmt.Sprintf("%d (%x)", parameter[0], parameter)
-			default:
-				if utf8.Valid(parameter) {
-					desc = fmt.Sprintf("%x (%q)", parameter, parameter)
-				} else {
-					// Try converting the parameter to an address and swap the endianness
-					// if the parameter is a 20-byte value.
-					u, err := util.Uint160DecodeBytesBE(parameter)
-					if err == nil {
-						desc = fmt.Sprintf("%x (%q, %q)", parameter, address.Uint160ToString(u), "0x"+u.StringLE())
-					} else {
-						desc = fmt.Sprintf("%x", parameter)
-					}
-				}
-			}
+			desc = describeParameter(ctx, instr, parameter)
 		}
 
 		fmt.Fprintf(w, "%d\t%s\t%s%s\n", ctx.ip, instr, desc, cursor)
@@ -261,6 +258,55 @@ func (v *VM) PrintOps(out io.Writer) {
 	w.Flush()
 }
 
+// describeParameter returns human-readable description of the given non-nil
+// instruction parameter, ctx is expected to be positioned at the instruction.
+func describeParameter(ctx *Context, instr opcode.Opcode, parameter []byte) string {
+	switch instr {
+	case opcode.JMP, opcode.JMPIF, opcode.JMPIFNOT, opcode.CALL,
+		opcode.JMPEQ, opcode.JMPNE,
+		opcode.JMPGT, opcode.JMPGE, opcode.JMPLE, opcode.JMPLT,
+		opcode.JMPL, opcode.JMPIFL, opcode.JMPIFNOTL, opcode.CALLL,
+		opcode.JMPEQL, opcode.JMPNEL,
+		opcode.JMPGTL, opcode.JMPGEL, opcode.JMPLEL, opcode.JMPLTL,
+		opcode.PUSHA, opcode.ENDTRY, opcode.ENDTRYL:
+		return getOffsetDesc(ctx, parameter)
+	case opcode.TRY, opcode.TRYL:
+		catchP, finallyP := getTryParams(instr, parameter)
+		return fmt.Sprintf("catch %s, finally %s",
+			getOffsetDesc(ctx, catchP), getOffsetDesc(ctx, finallyP))
+	case opcode.INITSSLOT:
+		return fmt.Sprint(parameter[0])
+	case opcode.CONVERT, opcode.ISTYPE:
+		typ := stackitem.Type(parameter[0])
+		return fmt.Sprintf("%s (%x)", typ, parameter[0])
+	case opcode.INITSLOT:
+		return fmt.Sprintf("%d local, %d arg", parameter[0], parameter[1])
+	case opcode.SYSCALL:
+		name, err := interopnames.FromID(GetInteropID(parameter))
+		if err != nil {
+			name = "not found"
+		}
+		return fmt.Sprintf("%s (%x)", name, parameter)
+	case opcode.PUSHINT8, opcode.PUSHINT16, opcode.PUSHINT32,
+		opcode.PUSHINT64, opcode.PUSHINT128, opcode.PUSHINT256:
+		val := bigint.FromBytes(parameter)
+		return fmt.Sprintf("%d (%x)", val, parameter)
+	case opcode.LDLOC, opcode.STLOC, opcode.LDARG, opcode.STARG, opcode.LDSFLD, opcode.STSFLD:
+		return fmt.Sprintf("%d (%x)", parameter[0], parameter)
+	default:
+		if utf8.Valid(parameter) {
+			return fmt.Sprintf("%x (%q)", parameter, parameter)
+		}
+		// Try converting the parameter to an address and swap the endianness
+		// if the parameter is a 20-byte value.
+		u, err := util.Uint160DecodeBytesBE(parameter)
+		if err == nil {
+			return fmt.Sprintf("%x (%q, %q)", parameter, address.Uint160ToString(u), "0x"+u.StringLE())
+		}
+		return fmt.Sprintf("%x", parameter)
+	}
+}
+
 func getOffsetDesc(ctx *Context, parameter []byte) string {
 	offset, rOffset, err := calcJumpOffset(ctx, parameter)
 	if err != nil {
@@ -378,23 +424,43 @@ func (v *VM) LoadNEFMethod(exe *nef.File, manifest *manifest.Manifest, caller ut
 // It should be used for calling from native contracts.
 func (v *VM) loadScriptWithCallingHash(b []byte, exe *nef.File, manifest *manifest.Manifest, caller util.Uint160,
 	hash util.Uint160, f callflag.CallFlag, rvcount int, offset int, onContextUnload ContextUnloadCallback) {
-	v.checkInvocationStackSize()
 	ctx := NewContextWithParams(b, rvcount, offset)
+	ctx.sc.callFlag = f
+	ctx.sc.scriptHash = hash
+	ctx.sc.callingScriptHash = caller
+	ctx.sc.NEF = exe
+	ctx.sc.Manifest = manifest
+	ctx.sc.onUnload = onContextUnload
+	v.loadContext(ctx)
+}
+
+// LoadContext pushes the given context (restored with [DeserializeContext])
+// to the invocation stack, so that its execution continues from the
+// instruction pointer it has. The context must not be loaded into any VM
+// already.
+func (v *VM) LoadContext(ctx *Context) {
+	for _, s := range []Slot{ctx.sc.static, ctx.local, ctx.arguments} {
+		for i := range s {
+			v.refs.Add(s.Get(i))
+		}
+	}
+	v.loadContext(ctx)
+}
+
+// loadContext binds the given context to the VM stacks and pushes it to the
+// invocation stack.
+func (v *VM) loadContext(ctx *Context) {
+	v.checkInvocationStackSize()
 	parent := v.Context()
 	if parent != nil {
 		ctx.sc.callingContext = parent.sc
 		parent.sc.estack = v.estack
 	}
-	if rvcount != -1 || v.estack.Len() != 0 {
+	if ctx.retCount != -1 || v.estack.Len() != 0 {
 		v.estack = subStack(v.estack)
 	}
 	ctx.sc.estack = v.estack
 	initStack(&ctx.tryStack, "exception", nil)
-	ctx.sc.callFlag = f
-	ctx.sc.scriptHash = hash
-	ctx.sc.callingScriptHash = caller
-	ctx.sc.NEF = exe
-	ctx.sc.Manifest = manifest
 	if v.invTree != nil {
 		curTree := v.invTree
 		if parent != nil {
@@ -404,7 +470,6 @@ func (v *VM) loadScriptWithCallingHash(b []byte, exe *nef.File, manifest *manife
 		curTree.Calls = append(curTree.Calls, newTree)
 		ctx.sc.invTree = newTree
 	}
-	ctx.sc.onUnload = onContextUnload
 	v.istack = append(v.istack, ctx)
 }
 
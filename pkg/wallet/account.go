@@ -1,9 +1,11 @@
 package wallet
 
 import (
+	"crypto/elliptic"
 	"errors"
 	"fmt"
 	"slices"
+	"strings"
 
 	"github.com/nspcc-dev/neo-go/pkg/config/netmode"
 	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
@@ -13,6 +15,7 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/io"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
 	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm"
 	"github.com/nspcc-dev/neo-go/pkg/vm/emit"
 	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
 )
@@ -78,6 +81,52 @@ func (c Contract) ScriptHash() util.Uint160 {
 	return hash.Hash160(c.Script)
 }
 
+// String implements the fmt.Stringer interface. It returns the LE script hash
+// of the contract, its kind (signature, m-of-n multisignature, deployed or
+// non-standard contract) and the list of its parameters, like
+// "0x2b5f...e4b2 (2-of-3 multisignature) [parameter0:Signature, parameter1:Signature]".
+func (c Contract) String() string {
+	var kind string
+	if vm.IsSignatureContract(c.Script) {
+		kind = "signature"
+	} else if m, pubs, ok := vm.ParseMultiSigContract(c.Script); ok {
+		kind = fmt.Sprintf("%d-of-%d multisignature", m, len(pubs))
+	} else if c.Deployed {
+		kind = "deployed"
+	} else {
+		kind = "non-standard"
+	}
+	params := make([]string, 0, len(c.Parameters))
+	for _, p := range c.Parameters {
+		params = append(params, p.Name+":"+p.Type.String())
+	}
+	return fmt.Sprintf("0x%s (%s) [%s]", c.ScriptHash().StringLE(), kind, strings.Join(params, ", "))
+}
+
+// Validate checks that contract parameters match the standard signature or
+// multisignature verification script: such scripts expect exactly one or m
+// signature parameters correspondingly. Parameters of other contracts are not
+// checked. Returned errors wrap [ErrInvalidContract].
+func (c Contract) Validate() error {
+	var nSigs int
+	if vm.IsSignatureContract(c.Script) {
+		nSigs = 1
+	} else if m, _, ok := vm.ParseMultiSigContract(c.Script); ok {
+		nSigs = m
+	} else {
+		return nil
+	}
+	if len(c.Parameters) != nSigs {
+		return fmt.Errorf("%w: %d parameters instead of %d", ErrInvalidContract, len(c.Parameters), nSigs)
+	}
+	for i, p := range c.Parameters {
+		if p.Type != smartcontract.SignatureType {
+			return fmt.Errorf("%w: parameter %d has %s type instead of %s", ErrInvalidContract, i, p.Type, smartcontract.SignatureType)
+		}
+	}
+	return nil
+}
+
 // NewAccount creates a new Account with a random generated PrivateKey.
 func NewAccount() (*Account, error) {
 	priv, err := keys.NewPrivateKey()
@@ -168,11 +217,17 @@ func (a *Account) CanSign() bool {
 	return !a.Locked && a.privateKey != nil
 }
 
-// GetVerificationScript returns account's verification script.
+// GetVerificationScript returns account's verification script. It's the
+// contract script if the account has one or a standard signature script
+// derived from the account key otherwise. Nil is returned for accounts having
+// neither a contract nor a decrypted key.
 func (a *Account) GetVerificationScript() []byte {
 	if a.Contract != nil {
 		return a.Contract.Script
 	}
+	if a.privateKey == nil {
+		return nil
+	}
 	return a.privateKey.PublicKey().GetVerificationScript()
 }
 
@@ -180,20 +235,32 @@ func (a *Account) GetVerificationScript() []byte {
 // if anything goes wrong. After the decryption Account can be used to sign
 // things unless it's locked. Don't decrypt the key unless you want to sign
 // something and don't forget to call Close after use for maximum safety.
+// Decrypting an already decrypted account with the correct passphrase keeps
+// the key it already has.
 func (a *Account) Decrypt(passphrase string, scrypt keys.ScryptParams) error {
-	var err error
-
 	if a.EncryptedWIF == "" {
 		return errors.New("no encrypted wif in the account")
 	}
-	a.privateKey, err = keys.NEP2Decrypt(a.EncryptedWIF, passphrase, scrypt)
+	pk, err := keys.NEP2Decrypt(a.EncryptedWIF, passphrase, scrypt)
 	if err != nil {
+		a.privateKey = nil
 		return err
 	}
+	if a.privateKey != nil && a.privateKey.WIF() == pk.WIF() {
+		pk.Destroy()
+		return nil
+	}
+	a.privateKey = pk
 
 	return nil
 }
 
+// IsDecrypted returns true if the account has a decrypted private key inside.
+// Unlike CanSign it doesn't take into account whether the account is locked.
+func (a *Account) IsDecrypted() bool {
+	return a.privateKey != nil
+}
+
 // Encrypt encrypts the wallet's PrivateKey with the given passphrase
 // under the NEP-2 standard.
 func (a *Account) Encrypt(passphrase string, scrypt keys.ScryptParams) error {
@@ -205,6 +272,18 @@ func (a *Account) Encrypt(passphrase string, scrypt keys.ScryptParams) error {
 	return nil
 }
 
+// ExportWIF returns WIF of the account's private key. The account must be
+// decrypted and not locked.
+func (a *Account) ExportWIF() (string, error) {
+	if a.Locked {
+		return "", errors.New("account is locked")
+	}
+	if a.privateKey == nil {
+		return "", errors.New("account key is not available (need to decrypt?)")
+	}
+	return a.privateKey.WIF(), nil
+}
+
 // PrivateKey returns private key corresponding to the account if it's unlocked.
 // Please be very careful when using it, do not copy its contents and do not
 // keep a pointer to it unless you absolutely need to. Most of the time you can
@@ -223,6 +302,59 @@ func (a *Account) PublicKey() *keys.PublicKey {
 	return a.privateKey.PublicKey()
 }
 
+// GetPublicKeys returns public keys from the account's verification script in
+// the order they're specified there, it's a single key for standard signature
+// accounts and all keys for multisignature ones. An error is returned if the
+// verification script is not available or it's neither a signature nor a
+// multisignature one.
+func (a *Account) GetPublicKeys() ([]*keys.PublicKey, error) {
+	script := a.GetVerificationScript()
+	if script == nil {
+		return nil, errors.New("no verification script")
+	}
+	var rawPubs [][]byte
+	if pub, ok := vm.ParseSignatureContract(script); ok {
+		rawPubs = [][]byte{pub}
+	} else if _, pubs, ok := vm.ParseMultiSigContract(script); ok {
+		rawPubs = pubs
+	} else {
+		return nil, errors.New("verification script is neither a signature nor a multisignature one")
+	}
+	return parsePublicKeys(rawPubs)
+}
+
+// GetSignatureParts parses the multisignature verification script of the
+// given contract and returns the number of signatures required along with
+// public keys in the order they're specified in the script (which is also
+// the order of signatures in the invocation script). An error is returned
+// for contracts without script or with a non-multisignature one.
+func GetSignatureParts(contract *Contract) (int, []*keys.PublicKey, error) {
+	if contract == nil || contract.Script == nil {
+		return 0, nil, errors.New("no verification script")
+	}
+	threshold, rawPubs, ok := vm.ParseMultiSigContract(contract.Script)
+	if !ok {
+		return 0, nil, errors.New("verification script is not a multisignature one")
+	}
+	pubs, err := parsePublicKeys(rawPubs)
+	if err != nil {
+		return 0, nil, err
+	}
+	return threshold, pubs, nil
+}
+
+func parsePublicKeys(rawPubs [][]byte) ([]*keys.PublicKey, error) {
+	res := make([]*keys.PublicKey, len(rawPubs))
+	for i := range rawPubs {
+		pub, err := keys.NewPublicKeyFromBytes(rawPubs[i], elliptic.P256())
+		if err != nil {
+			return nil, fmt.Errorf("invalid public key #%d: %w", i, err)
+		}
+		res[i] = pub
+	}
+	return res, nil
+}
+
 // ScriptHash returns the script hash (account) that the Account.Address is
 // derived from. It never returns an error, so if this Account has an invalid
 // Address you'll just get a zero script hash.
@@ -244,6 +376,27 @@ func (a *Account) Close() {
 	a.privateKey = nil
 }
 
+// clone returns a deep copy of the account without the decrypted private key.
+func (a *Account) clone() *Account {
+	res := &Account{
+		scriptHash:   a.scriptHash,
+		Address:      a.Address,
+		EncryptedWIF: a.EncryptedWIF,
+		Label:        a.Label,
+		Locked:       a.Locked,
+		Default:      a.Default,
+	}
+	if a.Contract != nil {
+		res.Contract = &Contract{
+			Script:            slices.Clone(a.Contract.Script),
+			Parameters:        slices.Clone(a.Contract.Parameters),
+			Deployed:          a.Contract.Deployed,
+			InvocationBuilder: a.Contract.InvocationBuilder,
+		}
+	}
+	return res
+}
+
 // NewAccountFromWIF creates a new Account from the given WIF.
 func NewAccountFromWIF(wif string) (*Account, error) {
 	privKey, err := keys.NewPrivateKeyFromWIF(wif)
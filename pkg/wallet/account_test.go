@@ -2,6 +2,8 @@ package wallet
 
 import (
 	"encoding/json"
+	"fmt"
+	"slices"
 	"testing"
 
 	"github.com/nspcc-dev/neo-go/internal/keytestcases"
@@ -10,6 +12,8 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
 	"github.com/nspcc-dev/neo-go/pkg/encoding/address"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -40,6 +44,28 @@ func TestDecryptAccount(t *testing.T) {
 	require.Error(t, acc.Decrypt("qwerty", keys.NEP2ScryptParams()))
 }
 
+func TestAccount_DecryptTwice(t *testing.T) {
+	tc := keytestcases.Arr[0]
+	acc := &Account{EncryptedWIF: tc.EncryptedWif}
+	require.False(t, acc.IsDecrypted())
+
+	require.NoError(t, acc.Decrypt(tc.Passphrase, keys.NEP2ScryptParams()))
+	require.True(t, acc.IsDecrypted())
+	pk := acc.PrivateKey()
+
+	// The key already decrypted is kept.
+	require.NoError(t, acc.Decrypt(tc.Passphrase, keys.NEP2ScryptParams()))
+	require.Same(t, pk, acc.PrivateKey())
+
+	require.Error(t, acc.Decrypt("wrong"+tc.Passphrase, keys.NEP2ScryptParams()))
+	require.False(t, acc.IsDecrypted())
+
+	require.NoError(t, acc.Decrypt(tc.Passphrase, keys.NEP2ScryptParams()))
+	require.True(t, acc.IsDecrypted())
+	acc.Close()
+	require.False(t, acc.IsDecrypted())
+}
+
 func TestNewFromWif(t *testing.T) {
 	for _, testCase := range keytestcases.Arr {
 		acc, err := NewAccountFromWIF(testCase.Wif)
@@ -53,6 +79,28 @@ func TestNewFromWif(t *testing.T) {
 	}
 }
 
+func TestAccount_ExportWIF(t *testing.T) {
+	for _, tc := range keytestcases.Arr {
+		if tc.Invalid {
+			continue
+		}
+		acc, err := NewAccountFromWIF(tc.Wif)
+		require.NoError(t, err)
+		wif, err := acc.ExportWIF()
+		require.NoError(t, err)
+		require.Equal(t, tc.Wif, wif)
+
+		acc.Locked = true
+		_, err = acc.ExportWIF()
+		require.Error(t, err)
+
+		acc.Locked = false
+		acc.Close()
+		_, err = acc.ExportWIF()
+		require.Error(t, err)
+	}
+}
+
 func TestNewAccountFromEncryptedWIF(t *testing.T) {
 	for _, tc := range keytestcases.Arr {
 		acc, err := NewAccountFromEncryptedWIF(tc.EncryptedWif, tc.Passphrase, keys.NEP2ScryptParams())
@@ -173,6 +221,92 @@ func TestContract_ScriptHash(t *testing.T) {
 	require.Equal(t, hash.Hash160(script), c.ScriptHash())
 }
 
+func TestAccount_GetVerificationScript(t *testing.T) {
+	pk, err := keys.NewPrivateKey()
+	require.NoError(t, err)
+	script := pk.PublicKey().GetVerificationScript()
+
+	t.Run("contract", func(t *testing.T) {
+		acc := NewAccountFromPrivateKey(pk)
+		require.Equal(t, script, acc.GetVerificationScript())
+
+		acc.Contract.Script = []byte{byte(opcode.PUSHT)}
+		require.Equal(t, []byte{byte(opcode.PUSHT)}, acc.GetVerificationScript())
+	})
+	t.Run("key only", func(t *testing.T) {
+		acc := NewAccountFromPrivateKey(pk)
+		acc.Contract = nil
+		require.Equal(t, script, acc.GetVerificationScript())
+	})
+	t.Run("watch-only", func(t *testing.T) {
+		acc := &Account{Address: address.Uint160ToString(hash.Hash160(script))}
+		require.Nil(t, acc.GetVerificationScript())
+	})
+}
+
+func TestContract_Validate(t *testing.T) {
+	pks := make([]*keys.PublicKey, 3)
+	for i := range pks {
+		pk, err := keys.NewPrivateKey()
+		require.NoError(t, err)
+		pks[i] = pk.PublicKey()
+	}
+	t.Run("single signature", func(t *testing.T) {
+		c := Contract{Script: pks[0].GetVerificationScript(), Parameters: getContractParams(1)}
+		require.NoError(t, c.Validate())
+
+		c.Parameters = nil
+		require.ErrorIs(t, c.Validate(), ErrInvalidContract)
+
+		c.Parameters = []ContractParam{{Name: "parameter0", Type: smartcontract.IntegerType}}
+		require.ErrorIs(t, c.Validate(), ErrInvalidContract)
+	})
+	t.Run("multisignature", func(t *testing.T) {
+		script, err := smartcontract.CreateMultiSigRedeemScript(2, pks)
+		require.NoError(t, err)
+		c := Contract{Script: script, Parameters: getContractParams(2)}
+		require.NoError(t, c.Validate())
+
+		c.Parameters = getContractParams(3)
+		require.ErrorIs(t, c.Validate(), ErrInvalidContract)
+	})
+	t.Run("custom contract", func(t *testing.T) {
+		c := Contract{Script: []byte{byte(opcode.PUSHT)}}
+		require.NoError(t, c.Validate())
+	})
+}
+
+func TestContract_String(t *testing.T) {
+	pks := make([]*keys.PublicKey, 3)
+	for i := range pks {
+		pk, err := keys.NewPrivateKey()
+		require.NoError(t, err)
+		pks[i] = pk.PublicKey()
+	}
+	t.Run("single signature", func(t *testing.T) {
+		c := &Contract{Script: pks[0].GetVerificationScript(), Parameters: getContractParams(1)}
+		require.Equal(t, "0x"+c.ScriptHash().StringLE()+" (signature) [parameter0:Signature]", c.String())
+	})
+	t.Run("multisignature", func(t *testing.T) {
+		script, err := smartcontract.CreateMultiSigRedeemScript(2, pks)
+		require.NoError(t, err)
+		c := &Contract{Script: script, Parameters: getContractParams(2)}
+		require.Equal(t, "0x"+c.ScriptHash().StringLE()+" (2-of-3 multisignature) [parameter0:Signature, parameter1:Signature]",
+			fmt.Sprint(c))
+	})
+	t.Run("deployed", func(t *testing.T) {
+		c := Contract{
+			Script:     []byte{byte(opcode.PUSHT)},
+			Parameters: []ContractParam{{Name: "amount", Type: smartcontract.IntegerType}},
+			Deployed:   true,
+		}
+		require.Equal(t, "0x"+c.ScriptHash().StringLE()+" (deployed) [amount:Integer]", c.String())
+		c.Deployed = false
+		c.Parameters = nil
+		require.Equal(t, "0x"+c.ScriptHash().StringLE()+" (non-standard) []", c.String())
+	})
+}
+
 func TestAccount_ConvertMultisig(t *testing.T) {
 	// test is based on a wallet1_solo.json accounts from neo-local
 	a, err := NewAccountFromWIF("KxyjQ8eUa4FHt3Gvioyt1Wz29cTUrE4eTqX3yFSk1YFCsPL8uNsY")
@@ -241,3 +375,75 @@ func compareFields(t *testing.T, tk keytestcases.Ktype, acc *Account) {
 	want, have = tk.PrivateKey, acc.privateKey.String()
 	require.Equalf(t, want, have, "expected priv key %s got %s", want, have)
 }
+
+func TestAccount_GetPublicKeys(t *testing.T) {
+	hexs := []string{
+		"02b3622bf4017bdfe317c58aed5f4c753f206b7db896046fa7d774bbc4bf7f8dc2",
+		"02103a7f7dd016558597f7960d27c516a4394fd968b9e65155eb4b013e4040406e",
+		"02a7bc55fe8684e0119768d104ba30795bdcc86619e864add26156723ed185cd62",
+		"03d90c07df63e690ce77912e10ab51acc944b66860237b608c4f8f8309e71ee699",
+	}
+
+	t.Run("single signature", func(t *testing.T) {
+		a, err := NewAccount()
+		require.NoError(t, err)
+		pubs, err := a.GetPublicKeys()
+		require.NoError(t, err)
+		require.Equal(t, []*keys.PublicKey{a.PublicKey()}, pubs)
+	})
+	t.Run("multisignature", func(t *testing.T) {
+		a, err := NewAccountFromWIF("KxyjQ8eUa4FHt3Gvioyt1Wz29cTUrE4eTqX3yFSk1YFCsPL8uNsY")
+		require.NoError(t, err)
+		expected := convertPubs(t, hexs)
+		require.NoError(t, a.ConvertMultisig(3, expected))
+
+		pubs, err := a.GetPublicKeys()
+		require.NoError(t, err)
+		slices.SortFunc(expected, (*keys.PublicKey).Cmp) // Multisig script has keys sorted.
+		require.Equal(t, expected, pubs)
+	})
+	t.Run("no script", func(t *testing.T) {
+		a := &Account{}
+		_, err := a.GetPublicKeys()
+		require.Error(t, err)
+	})
+	t.Run("non-standard script", func(t *testing.T) {
+		a := NewContractAccount(util.Uint160{1, 2, 3})
+		a.Contract.Script = []byte{byte(opcode.PUSHT)}
+		_, err := a.GetPublicKeys()
+		require.Error(t, err)
+	})
+}
+
+func TestGetSignatureParts(t *testing.T) {
+	hexs := []string{
+		"02b3622bf4017bdfe317c58aed5f4c753f206b7db896046fa7d774bbc4bf7f8dc2",
+		"02103a7f7dd016558597f7960d27c516a4394fd968b9e65155eb4b013e4040406e",
+		"02a7bc55fe8684e0119768d104ba30795bdcc86619e864add26156723ed185cd62",
+	}
+
+	t.Run("multisignature", func(t *testing.T) {
+		a, err := NewAccountFromWIF("KxyjQ8eUa4FHt3Gvioyt1Wz29cTUrE4eTqX3yFSk1YFCsPL8uNsY")
+		require.NoError(t, err)
+		expected := convertPubs(t, hexs)
+		require.NoError(t, a.ConvertMultisig(2, expected))
+
+		threshold, pubs, err := GetSignatureParts(a.Contract)
+		require.NoError(t, err)
+		require.Equal(t, 2, threshold)
+		slices.SortFunc(expected, (*keys.PublicKey).Cmp) // Multisig script has keys sorted.
+		require.Equal(t, expected, pubs)
+	})
+	t.Run("no script", func(t *testing.T) {
+		_, _, err := GetSignatureParts(nil)
+		require.Error(t, err)
+		_, _, err = GetSignatureParts(&Contract{})
+		require.Error(t, err)
+	})
+	t.Run("single signature", func(t *testing.T) {
+		a, err := NewAccount()
+		require.NoError(t, err)
+		_, _, err = GetSignatureParts(a.Contract)
+		require.Error(t, err)
+	})
+}
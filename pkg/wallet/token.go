@@ -1,10 +1,22 @@
 package wallet
 
 import (
+	"errors"
+	"fmt"
+
 	"github.com/nspcc-dev/neo-go/pkg/encoding/address"
 	"github.com/nspcc-dev/neo-go/pkg/util"
 )
 
+const (
+	// MaxTokenSymbolLen is the maximum token symbol length accepted by
+	// [NewTokenChecked].
+	MaxTokenSymbolLen = 32
+	// MaxTokenDecimals is the maximum number of token decimals accepted by
+	// [NewTokenChecked].
+	MaxTokenDecimals = 18
+)
+
 // Token represents an imported token contract.
 type Token struct {
 	Name     string       `json:"name"`
@@ -14,7 +26,8 @@ type Token struct {
 	Standard string       `json:"standard"`
 }
 
-// NewToken returns the new token contract info.
+// NewToken returns the new token contract info. It doesn't check the data
+// provided, use [NewTokenChecked] for untrusted sources.
 func NewToken(tokenHash util.Uint160, name, symbol string, decimals int64, standardName string) *Token {
 	return &Token{
 		Name:     name,
@@ -25,6 +38,22 @@ func NewToken(tokenHash util.Uint160, name, symbol string, decimals int64, stand
 	}
 }
 
+// NewTokenChecked is similar to [NewToken], but it returns an error if the
+// symbol is empty or longer than [MaxTokenSymbolLen] or if decimals are
+// negative or exceed [MaxTokenDecimals].
+func NewTokenChecked(tokenHash util.Uint160, name, symbol string, decimals int64, standardName string) (*Token, error) {
+	if len(symbol) == 0 {
+		return nil, errors.New("empty token symbol")
+	}
+	if len(symbol) > MaxTokenSymbolLen {
+		return nil, fmt.Errorf("token symbol is too long: %d > %d", len(symbol), MaxTokenSymbolLen)
+	}
+	if decimals < 0 || decimals > MaxTokenDecimals {
+		return nil, fmt.Errorf("invalid token decimals: %d (0..%d expected)", decimals, MaxTokenDecimals)
+	}
+	return NewToken(tokenHash, name, symbol, decimals, standardName), nil
+}
+
 // Address returns token address from hash.
 func (t *Token) Address() string {
 	return address.Uint160ToString(t.Hash)
@@ -2,6 +2,7 @@ package wallet
 
 import (
 	"encoding/json"
+	"strings"
 	"testing"
 
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract/manifest"
@@ -28,3 +29,29 @@ func TestToken_MarshalJSON(t *testing.T) {
 	require.NoError(t, json.Unmarshal(data, actual))
 	require.Equal(t, tok, actual)
 }
+
+func TestNewTokenChecked(t *testing.T) {
+	h := util.Uint160{1, 2, 3}
+
+	tok, err := NewTokenChecked(h, "Token", "TOK", MaxTokenDecimals, manifest.NEP17StandardName)
+	require.NoError(t, err)
+	require.Equal(t, NewToken(h, "Token", "TOK", MaxTokenDecimals, manifest.NEP17StandardName), tok)
+
+	_, err = NewTokenChecked(h, "Token", strings.Repeat("T", MaxTokenSymbolLen), 0, manifest.NEP11StandardName)
+	require.NoError(t, err)
+
+	for name, tc := range map[string]struct {
+		symbol   string
+		decimals int64
+	}{
+		"empty symbol":      {"", 8},
+		"long symbol":       {strings.Repeat("T", MaxTokenSymbolLen+1), 8},
+		"negative decimals": {"TOK", -1},
+		"big decimals":      {"TOK", MaxTokenDecimals + 1},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewTokenChecked(h, "Token", tc.symbol, tc.decimals, manifest.NEP17StandardName)
+			require.Error(t, err)
+		})
+	}
+}
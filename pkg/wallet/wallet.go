@@ -6,7 +6,9 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
+	"path/filepath"
 
 	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
 	"github.com/nspcc-dev/neo-go/pkg/encoding/address"
@@ -24,6 +26,25 @@ var (
 	// for instance with [NewInMemoryWallet] or [NewWalletFromBytes].
 	// Despite this, there was an attempt to save it via [Wallet.Save] or [Wallet.SavePretty] without [Wallet.SetPath].
 	ErrPathIsEmpty = errors.New("path is empty")
+
+	// ErrWeakScrypt is returned by [Wallet.SaveWithMinScrypt] if the wallet
+	// was saved with scrypt parameters weaker than the required minimum
+	// because no passphrase was provided to upgrade them.
+	ErrWeakScrypt = errors.New("scrypt parameters are weaker than required")
+
+	// ErrUnsupportedVersion is returned for wallets of unknown versions.
+	ErrUnsupportedVersion = errors.New("unsupported wallet version")
+	// ErrInvalidScrypt is returned for wallets with invalid scrypt parameters.
+	ErrInvalidScrypt = errors.New("invalid scrypt parameters")
+	// ErrInvalidAddress is returned for accounts with addresses that can't be
+	// decoded or don't match account contracts.
+	ErrInvalidAddress = errors.New("invalid account address")
+	// ErrInvalidContract is returned for standard signature and multisignature
+	// contracts with parameters not matching their scripts.
+	ErrInvalidContract = errors.New("invalid account contract")
+	// ErrMultipleDefaultAccounts is returned for wallets having more than one
+	// default account.
+	ErrMultipleDefaultAccounts = errors.New("multiple default accounts")
 )
 
 // Wallet represents a NEO (NEP-2, NEP-6) compliant wallet.
@@ -41,6 +62,21 @@ type Wallet struct {
 	// This field can be empty.
 	Extra Extra `json:"extra"`
 
+	// OnAccountAdded is called (if set) for every account added to the
+	// wallet with [Wallet.AddAccount] (including the ones created by
+	// [Wallet.CreateAccount] and [Wallet.ImportWIF]).
+	OnAccountAdded func(*Account) `json:"-"`
+
+	// OnAccountRemoved is called (if set) for every account removed from the
+	// wallet with [Wallet.RemoveAccount].
+	OnAccountRemoved func(*Account) `json:"-"`
+
+	// UniqueLabels makes [Wallet.CreateAccount], [Wallet.ImportWIF],
+	// [Wallet.AddAccountChecked] and [Wallet.RenameAccount] reject non-empty labels
+	// that are already used by other wallet accounts. Duplicate labels are
+	// allowed by default.
+	UniqueLabels bool `json:"-"`
+
 	// Path where the wallet file is located..
 	path string
 }
@@ -81,6 +117,9 @@ func NewWalletFromFile(path string) (*Wallet, error) {
 	if err := json.NewDecoder(file).Decode(wall); err != nil {
 		return nil, fmt.Errorf("unmarshal wallet: %w", err)
 	}
+	if err := wall.check(); err != nil {
+		return nil, fmt.Errorf("invalid wallet: %w", err)
+	}
 	return wall, nil
 }
 
@@ -94,10 +133,49 @@ func NewWalletFromBytes(wallet []byte) (*Wallet, error) {
 	if err := json.NewDecoder(bytes.NewReader(wallet)).Decode(wall); err != nil {
 		return nil, fmt.Errorf("unmarshal wallet: %w", err)
 	}
+	if err := wall.check(); err != nil {
+		return nil, fmt.Errorf("invalid wallet: %w", err)
+	}
 
 	return wall, nil
 }
 
+// check migrates the loaded wallet to the current version if needed and
+// rejects wallets that can't be used at all. Account consistency is not
+// checked here, use [Wallet.Validate] for that.
+func (w *Wallet) check() error {
+	if err := w.Migrate(); err != nil {
+		return err
+	}
+	if err := w.Scrypt.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidScrypt, err)
+	}
+	return nil
+}
+
+// Migrate upgrades the wallet of an older layout to the current NEP-6 version.
+// Wallets without version predate NEP-6 versioning, they're upgraded by
+// setting the version and NEP-2 scrypt parameters if none are specified. An
+// error is returned for unknown versions. Migrate only changes the in-memory
+// wallet, use [Wallet.Save] to store the result.
+func (w *Wallet) Migrate() error {
+	if w.Version == "" {
+		if w.Scrypt == (keys.ScryptParams{}) {
+			w.Scrypt = keys.NEP2ScryptParams()
+		}
+		w.Version = walletVersion
+	}
+	return w.checkVersion()
+}
+
+// checkVersion returns an error if the wallet version is not supported.
+func (w *Wallet) checkVersion() error {
+	if w.Version != walletVersion {
+		return fmt.Errorf("%w %q (%q is expected)", ErrUnsupportedVersion, w.Version, walletVersion)
+	}
+	return nil
+}
+
 func newWallet(rw io.ReadWriter) *Wallet {
 	var path string
 	if f, ok := rw.(*os.File); ok {
@@ -114,6 +192,9 @@ func newWallet(rw io.ReadWriter) *Wallet {
 // CreateAccount generates a new account for the end user and encrypts
 // the private key with the given passphrase.
 func (w *Wallet) CreateAccount(name, passphrase string) error {
+	if err := w.checkLabel(name, nil); err != nil {
+		return err
+	}
 	acc, err := NewAccount()
 	if err != nil {
 		return err
@@ -122,13 +203,104 @@ func (w *Wallet) CreateAccount(name, passphrase string) error {
 	if err := acc.Encrypt(passphrase, w.Scrypt); err != nil {
 		return err
 	}
-	w.AddAccount(acc)
+	if err := w.AddAccountChecked(acc); err != nil {
+		return err
+	}
 	return w.Save()
 }
 
 // AddAccount adds an existing Account to the wallet.
 func (w *Wallet) AddAccount(acc *Account) {
 	w.Accounts = append(w.Accounts, acc)
+	if w.OnAccountAdded != nil {
+		w.OnAccountAdded(acc)
+	}
+}
+
+// AddAccountChecked is the same as [Wallet.AddAccount], but it fails if the
+// account label is already used and [Wallet.UniqueLabels] is set.
+func (w *Wallet) AddAccountChecked(acc *Account) error {
+	if err := w.checkLabel(acc.Label, nil); err != nil {
+		return err
+	}
+	w.AddAccount(acc)
+	return nil
+}
+
+// checkLabel returns an error if [Wallet.UniqueLabels] is set and the given
+// non-empty label is used by any wallet account other than except.
+func (w *Wallet) checkLabel(label string, except *Account) error {
+	if !w.UniqueLabels || label == "" {
+		return nil
+	}
+	for _, acc := range w.Accounts {
+		if acc != except && acc.Label == label {
+			return fmt.Errorf("label '%s' is already used by account %s", label, acc.Address)
+		}
+	}
+	return nil
+}
+
+// ImportWIF creates a new single-signature Account from the given WIF, sets its
+// label, encrypts its private key with the given passphrase using wallet's
+// scrypt parameters and adds it to the wallet. The wallet is not saved.
+func (w *Wallet) ImportWIF(wif, label, pass string) (*Account, error) {
+	acc, err := NewAccountFromWIF(wif)
+	if err != nil {
+		return nil, err
+	}
+	acc.Label = label
+	if err := acc.Encrypt(pass, w.Scrypt); err != nil {
+		return nil, err
+	}
+	if err := w.AddAccountChecked(acc); err != nil {
+		return nil, err
+	}
+	return acc, nil
+}
+
+// Validate checks the wallet integrity: its version and scrypt parameters
+// must be supported, there can be at most one default account, every account
+// should have a valid address matching its verification script (if the
+// account has non-deployed contract) and standard contract parameters should
+// match their scripts (see [Contract.Validate]). The first violation found is
+// returned, it wraps one of ErrUnsupportedVersion, ErrInvalidScrypt,
+// ErrInvalidAddress, ErrInvalidContract or ErrMultipleDefaultAccounts.
+func (w *Wallet) Validate() error {
+	if err := w.checkVersion(); err != nil {
+		return err
+	}
+	if err := w.Scrypt.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidScrypt, err)
+	}
+	var defaultAcc *Account
+	for i, acc := range w.Accounts {
+		// Accounts without address can't be checked against their
+		// contracts, they're allowed for compatibility.
+		if acc.Address != "" {
+			h, err := address.StringToUint160(acc.Address)
+			if err != nil {
+				return fmt.Errorf("account #%d: %w %q: %w", i, ErrInvalidAddress, acc.Address, err)
+			}
+			if acc.Contract != nil && !acc.Contract.Deployed {
+				if sh := acc.Contract.ScriptHash(); !sh.Equals(h) {
+					return fmt.Errorf("account #%d: %w %s: contract script hash is %s", i, ErrInvalidAddress, acc.Address, address.Uint160ToString(sh))
+				}
+			}
+		}
+		if acc.Contract != nil {
+			if err := acc.Contract.Validate(); err != nil {
+				return fmt.Errorf("account #%d: %w", i, err)
+			}
+		}
+		if acc.Default {
+			if defaultAcc != nil {
+				return fmt.Errorf("%w: %s and %s", ErrMultipleDefaultAccounts, defaultAcc.Address, acc.Address)
+			}
+			defaultAcc = acc
+		}
+	}
+	return nil
 }
 
 // RemoveAccount removes an Account with the specified addr
@@ -138,12 +310,28 @@ func (w *Wallet) RemoveAccount(addr string) error {
 		if acc.Address == addr {
 			copy(w.Accounts[i:], w.Accounts[i+1:])
 			w.Accounts = w.Accounts[:len(w.Accounts)-1]
+			if w.OnAccountRemoved != nil {
+				w.OnAccountRemoved(acc)
+			}
 			return nil
 		}
 	}
 	return errors.New("account wasn't found")
 }
 
+// RenameAccount sets the label of the Account with the specified script hash.
+func (w *Wallet) RenameAccount(scriptHash util.Uint160, newLabel string) error {
+	acc := w.GetAccount(scriptHash)
+	if acc == nil {
+		return errors.New("account wasn't found")
+	}
+	if err := w.checkLabel(newLabel, acc); err != nil {
+		return err
+	}
+	acc.Label = newLabel
+	return nil
+}
+
 // AddToken adds a new token to a wallet.
 func (w *Wallet) AddToken(tok *Token) {
 	w.Extra.Tokens = append(w.Extra.Tokens, tok)
@@ -161,6 +349,19 @@ func (w *Wallet) RemoveToken(h util.Uint160) error {
 	return errors.New("token wasn't found")
 }
 
+// UpdateToken sets the symbol and decimals of the token with the specified
+// hash stored in the wallet.
+func (w *Wallet) UpdateToken(h util.Uint160, symbol string, decimals int64) error {
+	for _, tok := range w.Extra.Tokens {
+		if tok.Hash.Equals(h) {
+			tok.Symbol = symbol
+			tok.Decimals = decimals
+			return nil
+		}
+	}
+	return errors.New("token wasn't found")
+}
+
 // Path returns the location of the wallet on the filesystem.
 func (w *Wallet) Path() string {
 	return w.path
@@ -196,12 +397,131 @@ func (w *Wallet) SavePretty() error {
 	return w.writeRaw(data)
 }
 
+// SaveAs saves the wallet data to the file located at the given path and
+// makes it the wallet's path for subsequent saves. The file the wallet was
+// previously saved to is left intact. The wallet's path is not changed if
+// saving fails.
+//
+// Returns [ErrPathIsEmpty] if the given path is empty.
+func (w *Wallet) SaveAs(path string) error {
+	if path == "" {
+		return ErrPathIsEmpty
+	}
+	data, err := json.Marshal(w)
+	if err != nil {
+		return err
+	}
+	err = writeFileAtomic(path, data)
+	if err != nil {
+		return err
+	}
+	w.path = path
+	return nil
+}
+
+// SaveWithMinScrypt saves the wallet the same way [Wallet.Save] does, but if
+// the wallet's scrypt parameters are weaker than minParams, accounts are re-encrypted
+// with the upgraded parameters (see [Wallet.UpgradeScrypt]) before saving.
+// Without the passphrase the wallet is saved as is and [ErrWeakScrypt] is
+// returned as a warning.
+func (w *Wallet) SaveWithMinScrypt(minParams keys.ScryptParams, passphrase string) error {
+	if w.Scrypt.IsWeakerThan(minParams) {
+		if passphrase == "" {
+			if err := w.Save(); err != nil {
+				return err
+			}
+			return fmt.Errorf("%w: n %d, r %d, p %d", ErrWeakScrypt, w.Scrypt.N, w.Scrypt.R, w.Scrypt.P)
+		}
+		if err := w.UpgradeScrypt(minParams, passphrase); err != nil {
+			return err
+		}
+	}
+	return w.Save()
+}
+
+// UpgradeScrypt re-encrypts all wallet accounts having encrypted keys with the
+// given passphrase using scrypt parameters that are at least as strong as both
+// current wallet parameters and minParams. Accounts are either all re-encrypted or
+// left untouched if any of them can't be decrypted. Decrypted accounts stay
+// decrypted. UpgradeScrypt only changes the in-memory wallet, use [Wallet.Save]
+// to store the result.
+func (w *Wallet) UpgradeScrypt(minParams keys.ScryptParams, passphrase string) error {
+	params := keys.ScryptParams{
+		N: max(w.Scrypt.N, minParams.N),
+		R: max(w.Scrypt.R, minParams.R),
+		P: max(w.Scrypt.P, minParams.P),
+	}
+	if err := params.Validate(); err != nil {
+		return err
+	}
+	wifs := make([]string, len(w.Accounts))
+	for i, acc := range w.Accounts {
+		if acc.EncryptedWIF == "" {
+			continue
+		}
+		priv, err := keys.NEP2Decrypt(acc.EncryptedWIF, passphrase, w.Scrypt)
+		if err != nil {
+			return fmt.Errorf("account %s: %w", acc.Address, err)
+		}
+		wifs[i], err = keys.NEP2Encrypt(priv, passphrase, params)
+		priv.Destroy()
+		if err != nil {
+			return fmt.Errorf("account %s: %w", acc.Address, err)
+		}
+	}
+	for i, acc := range w.Accounts {
+		if wifs[i] != "" {
+			acc.EncryptedWIF = wifs[i]
+		}
+	}
+	w.Scrypt = params
+	return nil
+}
+
 func (w *Wallet) writeRaw(data []byte) error {
 	if w.path == "" {
 		return ErrPathIsEmpty
 	}
 
-	return os.WriteFile(w.path, data, 0644)
+	return writeFileAtomic(w.path, data)
+}
+
+// writeFileAtomic writes data to a temporary file in the same directory and
+// then renames it to path, so that the wallet file is either updated
+// completely or not changed at all. Permissions of the existing file are
+// preserved, new files are created with 0644 mode.
+func writeFileAtomic(path string, data []byte) error {
+	var perm fs.FileMode = 0644
+	if fi, err := os.Stat(path); err == nil {
+		perm = fi.Mode().Perm()
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	tmpName := tmp.Name()
+	_, err = tmp.Write(data)
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(tmpName, perm)
+	}
+	if err == nil {
+		// It's MoveFileEx with MOVEFILE_REPLACE_EXISTING on Windows, so
+		// the old file is replaced there as well and is never removed
+		// before the new one takes its place.
+		err = os.Rename(tmpName, path)
+	}
+	if err != nil {
+		_ = os.Remove(tmpName)
+	}
+	return err
 }
 
 // JSON outputs a pretty JSON representation of the wallet.
@@ -218,6 +538,29 @@ func (w *Wallet) Close() {
 	}
 }
 
+// Clone returns a deep copy of the wallet not bound to any file. Decrypted
+// private keys and account hooks are not copied, so accounts of the clone
+// need to be decrypted again to sign anything.
+func (w *Wallet) Clone() *Wallet {
+	res := &Wallet{
+		Version:      w.Version,
+		Accounts:     make([]*Account, 0, len(w.Accounts)),
+		Scrypt:       w.Scrypt,
+		UniqueLabels: w.UniqueLabels,
+		Extra: Extra{
+			Tokens: make([]*Token, 0, len(w.Extra.Tokens)),
+		},
+	}
+	for _, acc := range w.Accounts {
+		res.Accounts = append(res.Accounts, acc.clone())
+	}
+	for _, tok := range w.Extra.Tokens {
+		t := *tok
+		res.Extra.Tokens = append(res.Extra.Tokens, &t)
+	}
+	return res
+}
+
 // GetAccount returns an account corresponding to the provided scripthash.
 func (w *Wallet) GetAccount(h util.Uint160) *Account {
 	addr := address.Uint160ToString(h)
@@ -2,11 +2,17 @@ package wallet
 
 import (
 	"encoding/json"
+	"maps"
+	"os"
 	"path"
 	"path/filepath"
+	"runtime"
+	"slices"
 	"testing"
 
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
 	"github.com/nspcc-dev/neo-go/pkg/encoding/address"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract/manifest"
 	"github.com/nspcc-dev/neo-go/pkg/util"
 	"github.com/stretchr/testify/assert"
@@ -72,6 +78,35 @@ func TestAddAccount(t *testing.T) {
 	}
 }
 
+func TestInMemoryWallet(t *testing.T) {
+	w := NewInMemoryWallet()
+	require.Empty(t, w.Path())
+	acc, err := NewAccount()
+	require.NoError(t, err)
+	acc.Label = "first"
+	require.NoError(t, acc.Encrypt("pass", w.Scrypt))
+	w.AddAccount(acc)
+	acc, err = NewAccount()
+	require.NoError(t, err)
+	w.AddAccount(acc)
+
+	data, err := w.JSON()
+	require.NoError(t, err)
+	w2, err := NewWalletFromBytes(data)
+	require.NoError(t, err)
+	require.Len(t, w2.Accounts, 2)
+	require.Equal(t, w.Accounts[0].Address, w2.Accounts[0].Address)
+	require.Equal(t, w.Accounts[1].Address, w2.Accounts[1].Address)
+
+	require.ErrorIs(t, w.Save(), ErrPathIsEmpty)
+	require.ErrorIs(t, w.SavePretty(), ErrPathIsEmpty)
+
+	file := filepath.Join(t.TempDir(), walletTemplate)
+	require.NoError(t, w.SaveAs(file))
+	require.Equal(t, file, w.Path())
+	require.NoError(t, w.Save())
+}
+
 func TestPath(t *testing.T) {
 	wallet := checkWalletConstructor(t)
 
@@ -123,6 +158,85 @@ func TestSave(t *testing.T) {
 	}
 }
 
+func TestSave_Atomic(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, walletTemplate)
+	w := NewInMemoryWallet()
+	w.SetPath(file)
+	require.NoError(t, w.CreateAccount("first", "pass"))
+	require.NoError(t, w.SavePretty())
+	require.NoError(t, w.Save())
+
+	// Only the wallet file is left after saves, temporary ones are renamed.
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, walletTemplate, entries[0].Name())
+
+	w2, err := NewWalletFromFile(file)
+	require.NoError(t, err)
+	require.Equal(t, w.Accounts[0].Address, w2.Accounts[0].Address)
+
+	w.SetPath(filepath.Join(dir, "unknown", walletTemplate))
+	require.Error(t, w.Save())
+	entries, err = os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+}
+
+func TestSave_KeepsMode(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("no Unix file permissions on Windows")
+	}
+	file := filepath.Join(t.TempDir(), walletTemplate)
+	w := NewInMemoryWallet()
+	w.SetPath(file)
+	require.NoError(t, w.Save())
+	fi, err := os.Stat(file)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0644), fi.Mode().Perm())
+
+	require.NoError(t, os.Chmod(file, 0600))
+	require.NoError(t, w.CreateAccount("first", "pass"))
+	require.NoError(t, w.Save())
+	fi, err = os.Stat(file)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0600), fi.Mode().Perm())
+}
+
+func TestSaveAs(t *testing.T) {
+	w := checkWalletConstructor(t)
+	require.NoError(t, w.CreateAccount("first", "pass"))
+	oldPath := w.Path()
+
+	require.ErrorIs(t, w.SaveAs(""), ErrPathIsEmpty)
+	require.Equal(t, oldPath, w.Path())
+
+	require.Error(t, w.SaveAs(filepath.Join(t.TempDir(), "unknown", walletTemplate)))
+	require.Equal(t, oldPath, w.Path())
+
+	newPath := filepath.Join(t.TempDir(), walletTemplate)
+	require.NoError(t, w.SaveAs(newPath))
+	require.Equal(t, newPath, w.Path())
+
+	w2, err := NewWalletFromFile(newPath)
+	require.NoError(t, err)
+	require.Equal(t, newPath, w2.Path())
+	require.Equal(t, 1, len(w2.Accounts))
+	require.Equal(t, w.Accounts[0].Address, w2.Accounts[0].Address)
+
+	// Further changes go to the new location only.
+	require.NoError(t, w.CreateAccount("second", "pass"))
+	w2, err = NewWalletFromFile(newPath)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(w2.Accounts))
+
+	old, err := NewWalletFromFile(oldPath)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(old.Accounts))
+	require.NoError(t, os.Remove(oldPath))
+}
+
 func TestJSONMarshallUnmarshal(t *testing.T) {
 	wallet := checkWalletConstructor(t)
 
@@ -139,6 +253,60 @@ func TestJSONMarshallUnmarshal(t *testing.T) {
 	require.Equal(t, wallet.Scrypt, unmarshalledWallet.Scrypt)
 }
 
+func TestJSONMarshallUnmarshal_WatchOnly(t *testing.T) {
+	pk, err := keys.NewPrivateKey()
+	require.NoError(t, err)
+	pub := pk.PublicKey()
+
+	w := checkWalletConstructor(t)
+	withContract := &Account{
+		Address: address.Uint160ToString(pub.GetScriptHash()),
+		Label:   "watch",
+		Contract: &Contract{
+			Script:     pub.GetVerificationScript(),
+			Parameters: []ContractParam{{Name: "parameter0", Type: smartcontract.SignatureType}},
+		},
+	}
+	noContract := &Account{
+		Address: address.Uint160ToString(util.Uint160{1, 2, 3}),
+	}
+	w.AddAccount(withContract)
+	w.AddAccount(noContract)
+	require.NoError(t, w.Save())
+
+	raw, err := os.ReadFile(w.Path())
+	require.NoError(t, err)
+	var accs struct {
+		Accounts []map[string]any `json:"accounts"`
+	}
+	require.NoError(t, json.Unmarshal(raw, &accs))
+	require.Len(t, accs.Accounts, 2)
+	for _, acc := range accs.Accounts {
+		require.ElementsMatch(t, []string{"address", "key", "label", "contract", "lock", "isDefault"}, slices.Collect(maps.Keys(acc)))
+		require.Equal(t, "", acc["key"])
+	}
+	require.Nil(t, accs.Accounts[1]["contract"])
+
+	w2, err := NewWalletFromFile(w.Path())
+	require.NoError(t, err)
+	require.Len(t, w2.Accounts, 2)
+	for i, acc := range w2.Accounts {
+		require.Equal(t, w.Accounts[i].ScriptHash(), acc.ScriptHash())
+		require.Equal(t, w.Accounts[i], acc)
+		require.Nil(t, acc.PrivateKey())
+		require.False(t, acc.CanSign())
+	}
+
+	t.Run("null key", func(t *testing.T) {
+		acc := new(Account)
+		require.NoError(t, json.Unmarshal([]byte(`{"address":"`+noContract.Address+`","key":null,"label":null,"contract":null,"lock":false,"isDefault":false}`), acc))
+		require.Equal(t, noContract.Address, acc.Address)
+		require.Equal(t, "", acc.EncryptedWIF)
+		require.Equal(t, "", acc.Label)
+		require.Nil(t, acc.Contract)
+	})
+}
+
 func checkWalletConstructor(t *testing.T) *Wallet {
 	tmpDir := t.TempDir()
 	file := filepath.Join(tmpDir, walletTemplate)
@@ -159,6 +327,111 @@ func TestWallet_AddToken(t *testing.T) {
 	require.Equal(t, 0, len(w.Extra.Tokens))
 }
 
+func TestWallet_Clone(t *testing.T) {
+	w, err := NewWalletFromFile("testdata/wallet1.json")
+	require.NoError(t, err)
+	w.AddToken(NewToken(util.Uint160{1, 2, 3}, "Rubl", "RUB", 2, manifest.NEP17StandardName))
+	require.NoError(t, w.Accounts[0].Decrypt("one", w.Scrypt))
+
+	orig, err := w.JSON()
+	require.NoError(t, err)
+
+	c := w.Clone()
+	require.Equal(t, "", c.Path())
+	cloned, err := c.JSON()
+	require.NoError(t, err)
+	require.JSONEq(t, string(orig), string(cloned))
+	require.False(t, c.Accounts[0].IsDecrypted())
+
+	c.Version = "2.0"
+	c.Scrypt.N = 1
+	c.Accounts[0].Label = "changed"
+	c.Accounts[0].EncryptedWIF = "changed"
+	c.Accounts[0].Contract.Script[0] ^= 0xff
+	c.Accounts[0].Contract.Parameters[0].Name = "changed"
+	c.Accounts = c.Accounts[:1]
+	c.Extra.Tokens[0].Symbol = "EUR"
+
+	after, err := w.JSON()
+	require.NoError(t, err)
+	require.JSONEq(t, string(orig), string(after))
+	require.True(t, w.Accounts[0].IsDecrypted())
+}
+
+func TestWallet_AccountHooks(t *testing.T) {
+	w := checkWalletConstructor(t)
+	var added, removed []*Account
+	w.OnAccountAdded = func(acc *Account) { added = append(added, acc) }
+	w.OnAccountRemoved = func(acc *Account) { removed = append(removed, acc) }
+
+	require.NoError(t, w.CreateAccount("first", "pass"))
+	require.Equal(t, []*Account{w.Accounts[0]}, added)
+
+	acc, err := NewAccount()
+	require.NoError(t, err)
+	w.AddAccount(acc)
+	require.Equal(t, []*Account{w.Accounts[0], acc}, added)
+	require.Nil(t, removed)
+
+	require.Error(t, w.RemoveAccount("unknown"))
+	require.Nil(t, removed)
+	require.NoError(t, w.RemoveAccount(acc.Address))
+	require.Equal(t, []*Account{acc}, removed)
+	require.Len(t, added, 2)
+}
+
+func TestWallet_UniqueLabels(t *testing.T) {
+	w := checkWalletConstructor(t)
+	require.NoError(t, w.CreateAccount("same", "pass"))
+	require.NoError(t, w.CreateAccount("same", "pass"))
+	require.Len(t, w.Accounts, 2)
+
+	w = checkWalletConstructor(t)
+	w.UniqueLabels = true
+	require.NoError(t, w.CreateAccount("first", "pass"))
+	require.Error(t, w.CreateAccount("first", "pass"))
+	require.Len(t, w.Accounts, 1)
+
+	acc, err := NewAccount()
+	require.NoError(t, err)
+	acc.Label = "first"
+	require.Error(t, w.AddAccountChecked(acc))
+	acc.Label = "second"
+	require.NoError(t, w.AddAccountChecked(acc))
+
+	unnamed, err := NewAccount()
+	require.NoError(t, err)
+	require.NoError(t, w.AddAccountChecked(unnamed))
+	require.NoError(t, w.RenameAccount(unnamed.ScriptHash(), ""))
+
+	require.Error(t, w.RenameAccount(acc.ScriptHash(), "first"))
+	require.NoError(t, w.RenameAccount(acc.ScriptHash(), "second"))
+	require.Equal(t, "second", acc.Label)
+
+	// AddAccount doesn't check labels.
+	dup, err := NewAccount()
+	require.NoError(t, err)
+	dup.Label = "first"
+	w.AddAccount(dup)
+	require.Len(t, w.Accounts, 4)
+}
+
+func TestWallet_UpdateToken(t *testing.T) {
+	w := checkWalletConstructor(t)
+	tok := NewToken(util.Uint160{1, 2, 3}, "Rubl", "RUB", 2, manifest.NEP17StandardName)
+	w.AddToken(tok)
+	require.Error(t, w.UpdateToken(util.Uint160{4, 5, 6}, "EUR", 8))
+	require.NoError(t, w.UpdateToken(tok.Hash, "RUR", 8))
+	require.NoError(t, w.Save())
+
+	w2, err := NewWalletFromFile(w.Path())
+	require.NoError(t, err)
+	require.Equal(t, 1, len(w2.Extra.Tokens))
+	require.Equal(t, "RUR", w2.Extra.Tokens[0].Symbol)
+	require.Equal(t, int64(8), w2.Extra.Tokens[0].Decimals)
+	require.Equal(t, "Rubl", w2.Extra.Tokens[0].Name)
+}
+
 func TestWallet_GetAccount(t *testing.T) {
 	wallet := checkWalletConstructor(t)
 	accounts := []*Account{
@@ -185,6 +458,49 @@ func TestWallet_GetAccount(t *testing.T) {
 	}
 }
 
+func TestWallet_RenameAccount(t *testing.T) {
+	w := checkWalletConstructor(t)
+	require.NoError(t, w.CreateAccount("old", "pass"))
+	h := w.Accounts[0].ScriptHash()
+
+	require.Error(t, w.RenameAccount(util.Uint160{1, 2, 3}, "new"))
+	require.NoError(t, w.RenameAccount(h, "new"))
+	require.Equal(t, "new", w.Accounts[0].Label)
+	require.NoError(t, w.Save())
+
+	w2, err := NewWalletFromFile(w.Path())
+	require.NoError(t, err)
+	require.Equal(t, "new", w2.GetAccount(h).Label)
+}
+
+func TestWallet_ImportWIF(t *testing.T) {
+	w := checkWalletConstructor(t)
+	pk, err := keys.NewPrivateKey()
+	require.NoError(t, err)
+
+	_, err = w.ImportWIF("not a wif", "bad", "pass")
+	require.Error(t, err)
+	require.Empty(t, w.Accounts)
+
+	acc, err := w.ImportWIF(pk.WIF(), "imported", "pass")
+	require.NoError(t, err)
+	require.Equal(t, []*Account{acc}, w.Accounts)
+	require.Equal(t, "imported", acc.Label)
+	require.Equal(t, pk.GetScriptHash(), acc.ScriptHash())
+	require.NoError(t, w.Save())
+
+	w2, err := NewWalletFromFile(w.Path())
+	require.NoError(t, err)
+	acc2 := w2.GetAccount(pk.GetScriptHash())
+	require.NotNil(t, acc2)
+	_, err = acc2.ExportWIF()
+	require.Error(t, err)
+	require.NoError(t, acc2.Decrypt("pass", w2.Scrypt))
+	wif, err := acc2.ExportWIF()
+	require.NoError(t, err)
+	require.Equal(t, pk.WIF(), wif)
+}
+
 func TestWalletGetChangeAddress(t *testing.T) {
 	w1, err := NewWalletFromFile("testdata/wallet1.json")
 	require.NoError(t, err)
@@ -215,6 +531,146 @@ func TestWalletForExamples(t *testing.T) {
 	require.Equal(t, "NbrUYaZgyhSkNoRo9ugRyEMdUZxrhkNaWB", w.Accounts[0].Address, "need to change `owner` in the example contracts")
 }
 
+func TestWallet_Validate(t *testing.T) {
+	load := func(t *testing.T) *Wallet {
+		w, err := NewWalletFromFile("testdata/wallet2.json")
+		require.NoError(t, err)
+		return w
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		w := load(t)
+		require.NoError(t, w.Validate())
+
+		w.AddAccount(NewContractAccount(util.Uint160{1, 2, 3})) // Deployed contract account.
+		require.NoError(t, w.Validate())
+	})
+	t.Run("unsupported version", func(t *testing.T) {
+		w := load(t)
+		w.Version = "2.0"
+		require.ErrorIs(t, w.Validate(), ErrUnsupportedVersion)
+	})
+	t.Run("invalid scrypt", func(t *testing.T) {
+		w := load(t)
+		w.Scrypt.N = 3
+		err := w.Validate()
+		require.ErrorIs(t, err, ErrInvalidScrypt)
+		require.ErrorContains(t, err, "invalid scrypt N 3")
+	})
+	t.Run("invalid address", func(t *testing.T) {
+		w := load(t)
+		w.Accounts[1].Address = "NotAnAddress"
+		require.ErrorIs(t, w.Validate(), ErrInvalidAddress)
+		require.ErrorContains(t, w.Validate(), "account #1")
+	})
+	t.Run("address mismatch", func(t *testing.T) {
+		w := load(t)
+		w.Accounts[0].Address = w.Accounts[1].Address
+		require.ErrorIs(t, w.Validate(), ErrInvalidAddress)
+	})
+	t.Run("invalid contract", func(t *testing.T) {
+		w := load(t)
+		w.Accounts[0].Contract.Parameters = append(w.Accounts[0].Contract.Parameters, ContractParam{Type: smartcontract.SignatureType})
+		require.ErrorIs(t, w.Validate(), ErrInvalidContract)
+	})
+	t.Run("multiple default accounts", func(t *testing.T) {
+		w := load(t)
+		w.Accounts[0].Default = true // Accounts[2] is a default one.
+		require.ErrorIs(t, w.Validate(), ErrMultipleDefaultAccounts)
+	})
+	t.Run("on load", func(t *testing.T) {
+		w := load(t)
+		w.Accounts[0].Default = true
+		data, err := w.JSON()
+		require.NoError(t, err)
+		loaded, err := NewWalletFromBytes(data)
+		require.NoError(t, err)
+		require.ErrorIs(t, loaded.Validate(), ErrMultipleDefaultAccounts)
+	})
+}
+
+func TestWallet_SaveWithMinScrypt(t *testing.T) {
+	var (
+		weak   = keys.ScryptParams{N: 2, R: 1, P: 1}
+		strong = keys.ScryptParams{N: 16, R: 2, P: 1}
+		file   = filepath.Join(t.TempDir(), "wallet.json")
+	)
+	w := NewInMemoryWallet()
+	w.SetPath(file)
+	w.Scrypt = weak
+	require.NoError(t, w.CreateAccount("acc", "pass"))
+	watchOnly, err := NewAccount()
+	require.NoError(t, err)
+	watchOnly.EncryptedWIF = ""
+	w.AddAccount(watchOnly)
+	wif := w.Accounts[0].EncryptedWIF
+
+	t.Run("no passphrase", func(t *testing.T) {
+		require.ErrorIs(t, w.SaveWithMinScrypt(strong, ""), ErrWeakScrypt)
+		saved, err := NewWalletFromFile(file)
+		require.NoError(t, err)
+		require.Equal(t, weak, saved.Scrypt)
+		require.Equal(t, wif, saved.Accounts[0].EncryptedWIF)
+	})
+	t.Run("wrong passphrase", func(t *testing.T) {
+		require.Error(t, w.SaveWithMinScrypt(strong, "wrong"))
+		require.Equal(t, weak, w.Scrypt)
+		require.Equal(t, wif, w.Accounts[0].EncryptedWIF)
+	})
+	t.Run("upgrade", func(t *testing.T) {
+		require.NoError(t, w.SaveWithMinScrypt(strong, "pass"))
+		saved, err := NewWalletFromFile(file)
+		require.NoError(t, err)
+		require.Equal(t, strong, saved.Scrypt)
+		require.NotEqual(t, wif, saved.Accounts[0].EncryptedWIF)
+		require.Equal(t, "", saved.Accounts[1].EncryptedWIF)
+		require.Error(t, saved.Accounts[0].Decrypt("pass", weak))
+		require.NoError(t, saved.Accounts[0].Decrypt("pass", saved.Scrypt))
+		require.Equal(t, w.Accounts[0].ScriptHash(), saved.Accounts[0].ScriptHash())
+	})
+	t.Run("strong enough", func(t *testing.T) {
+		wif := w.Accounts[0].EncryptedWIF
+		require.NoError(t, w.SaveWithMinScrypt(weak, ""))
+		require.Equal(t, strong, w.Scrypt)
+		require.Equal(t, wif, w.Accounts[0].EncryptedWIF)
+	})
+}
+
+func TestWallet_Migrate(t *testing.T) {
+	t.Run("unversioned", func(t *testing.T) {
+		w, err := NewWalletFromFile("testdata/wallet_unversioned.json")
+		require.NoError(t, err)
+		require.Equal(t, walletVersion, w.Version)
+		require.Equal(t, keys.ScryptParams{N: 2, R: 1, P: 1}, w.Scrypt)
+		require.Len(t, w.Accounts, 2)
+		require.NoError(t, w.Accounts[0].Decrypt("one", w.Scrypt))
+	})
+	t.Run("no scrypt", func(t *testing.T) {
+		w, err := NewWalletFromBytes([]byte(`{"accounts":[]}`))
+		require.NoError(t, err)
+		require.Equal(t, walletVersion, w.Version)
+		require.Equal(t, keys.NEP2ScryptParams(), w.Scrypt)
+	})
+	t.Run("unknown version", func(t *testing.T) {
+		_, err := NewWalletFromBytes([]byte(`{"version":"2.0","accounts":[],"scrypt":{"n":2,"r":1,"p":1}}`))
+		require.ErrorIs(t, err, ErrUnsupportedVersion)
+
+		w := &Wallet{Version: "0.1"}
+		require.Error(t, w.Migrate())
+	})
+}
+
+func TestInvalidScrypt(t *testing.T) {
+	data := []byte(`{"version":"1.0","accounts":[],"scrypt":{"n":0,"r":8,"p":8},"extra":{"Tokens":null}}`)
+	_, err := NewWalletFromBytes(data)
+	require.ErrorContains(t, err, "invalid scrypt N 0")
+
+	file := filepath.Join(t.TempDir(), walletTemplate)
+	require.NoError(t, os.WriteFile(file, data, 0644))
+	_, err = NewWalletFromFile(file)
+	require.ErrorContains(t, err, "invalid scrypt N 0")
+}
+
 func TestFromBytes(t *testing.T) {
 	wallet := checkWalletConstructor(t)
 	bts, err := wallet.JSON()